@@ -0,0 +1,78 @@
+package deck
+
+import (
+	"fmt"
+	"slices"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// accentThemeColors are the theme colors that can be used as the accent of a slide.
+var accentThemeColors = []string{"ACCENT1", "ACCENT2", "ACCENT3", "ACCENT4", "ACCENT5", "ACCENT6"}
+
+func validateAccent(accent string) error {
+	if accent == "" || slices.Contains(accentThemeColors, accent) {
+		return nil
+	}
+	return fmt.Errorf("invalid accent: %q (available: %v)", accent, accentThemeColors)
+}
+
+// readAccent returns the accent of the slide, which is the theme color of the first text run of the title
+// if it is one of the accent colors.
+func readAccent(p *slides.Page) string {
+	for _, element := range p.PageElements {
+		if element.Shape == nil || element.Shape.Placeholder == nil || element.Shape.Text == nil {
+			continue
+		}
+		if t := element.Shape.Placeholder.Type; t != "CENTERED_TITLE" && t != "TITLE" {
+			continue
+		}
+		for _, te := range element.Shape.Text.TextElements {
+			if te.TextRun == nil || te.TextRun.Style == nil {
+				continue
+			}
+			fg := te.TextRun.Style.ForegroundColor
+			if fg == nil || fg.OpaqueColor == nil || !slices.Contains(accentThemeColors, fg.OpaqueColor.ThemeColor) {
+				return ""
+			}
+			return fg.OpaqueColor.ThemeColor
+		}
+	}
+	return ""
+}
+
+// accentRequests returns the requests to color the titles and the lines (rules) of the slide with the accent.
+func accentRequests(p *slides.Page, titleIDs []string, accent string) []*slides.Request {
+	if accent == "" {
+		return nil
+	}
+	color := &slides.OpaqueColor{ThemeColor: accent}
+	var reqs []*slides.Request
+	for _, id := range titleIDs {
+		reqs = append(reqs, &slides.Request{
+			UpdateTextStyle: &slides.UpdateTextStyleRequest{
+				ObjectId:  id,
+				TextRange: &slides.Range{Type: "ALL"},
+				Style: &slides.TextStyle{
+					ForegroundColor: &slides.OptionalColor{OpaqueColor: color},
+				},
+				Fields: "foregroundColor",
+			},
+		})
+	}
+	for _, element := range p.PageElements {
+		if element.Line == nil {
+			continue
+		}
+		reqs = append(reqs, &slides.Request{
+			UpdateLineProperties: &slides.UpdateLinePropertiesRequest{
+				ObjectId: element.ObjectId,
+				LineProperties: &slides.LineProperties{
+					LineFill: &slides.LineFill{SolidFill: &slides.SolidFill{Color: color}},
+				},
+				Fields: "lineFill.solidFill.color",
+			},
+		})
+	}
+	return reqs
+}
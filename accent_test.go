@@ -0,0 +1,69 @@
+package deck
+
+import (
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestApplyAccent(t *testing.T) {
+	p := newFakePresentation("a")
+	p.Slides[0].PageElements = append(p.Slides[0].PageElements, &slides.PageElement{
+		ObjectId:  "slide-0-rule",
+		Transform: &slides.AffineTransform{},
+		Line:      &slides.Line{LineCategory: "STRAIGHT"},
+	})
+	s := &fakeServer{presentation: p}
+	d := newFakeDeck(t, s)
+
+	ss := Slides{{
+		Layout:      "Title and Content",
+		Titles:      []string{"b"},
+		TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "b"}}}}}},
+		Accent:      "ACCENT2",
+	}}
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+
+	var titleColored, ruleColored bool
+	for _, req := range s.batchUpdates {
+		for _, r := range req.Requests {
+			if u := r.UpdateTextStyle; u != nil && u.ObjectId == "slide-0-title" && u.Fields == "foregroundColor" {
+				if got := u.Style.ForegroundColor.OpaqueColor.ThemeColor; got != "ACCENT2" {
+					t.Errorf("got title color %q, want ACCENT2", got)
+				}
+				titleColored = true
+			}
+			if u := r.UpdateLineProperties; u != nil && u.ObjectId == "slide-0-rule" {
+				if got := u.LineProperties.LineFill.SolidFill.Color.ThemeColor; got != "ACCENT2" {
+					t.Errorf("got rule color %q, want ACCENT2", got)
+				}
+				ruleColored = true
+			}
+		}
+	}
+	if !titleColored {
+		t.Error("title run is not colored with the accent")
+	}
+	if !ruleColored {
+		t.Error("rule is not colored with the accent")
+	}
+
+	if _, err := d.Apply(t.Context(), Slides{{Titles: []string{"b"}, Accent: "RED"}}); err == nil {
+		t.Error("expected error for invalid accent but got none")
+	}
+}
+
+func TestReadAccent(t *testing.T) {
+	p := newFakePresentation("a").Slides[0]
+	if got := readAccent(p); got != "" {
+		t.Errorf("got accent %q, want none", got)
+	}
+	p.PageElements[0].Shape.Text.TextElements[1].TextRun.Style = &slides.TextStyle{
+		ForegroundColor: &slides.OptionalColor{OpaqueColor: &slides.OpaqueColor{ThemeColor: "ACCENT3"}},
+	}
+	if got := readAccent(p); got != "ACCENT3" {
+		t.Errorf("got accent %q, want ACCENT3", got)
+	}
+}
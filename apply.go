@@ -22,8 +22,25 @@ const (
 	descriptionBlockquoteTextboxFromMarkdown = "Blockquote textbox generated from markdown"
 )
 
+// markdownImageAlt returns the alternative text of the image generated from markdown,
+// which is stored in the alt text title while the description marks the image as generated.
+func markdownImageAlt(element *slides.PageElement) string {
+	if element.Description != descriptionImageFromMarkdown {
+		return ""
+	}
+	return element.Title
+}
+
+// imageLink returns the URL of the link set on the image, or an empty string if there is none.
+func imageLink(img *slides.Image) string {
+	if img.ImageProperties != nil && img.ImageProperties.Link != nil {
+		return img.ImageProperties.Link.Url
+	}
+	return ""
+}
+
 // Apply the markdown slides to the presentation.
-func (d *Deck) Apply(ctx context.Context, slides Slides) (err error) {
+func (d *Deck) Apply(ctx context.Context, slides Slides) (_ *ApplyResult, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
@@ -35,37 +52,87 @@ func (d *Deck) Apply(ctx context.Context, slides Slides) (err error) {
 }
 
 // ApplyPages applies the markdown slides to the presentation with the specified pages.
-func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err error) {
+// It returns the result of what has been changed.
+// With WithContinueOnImageError, it returns the result together with the errors of current images that failed to be preloaded.
+func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (_ *ApplyResult, err error) {
 	defer func() {
+		if err != nil {
+			d.stats().addError(ErrorTypeApply)
+		}
 		err = errors.WithStack(err)
 	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stats().applies.Add(1)
+	result := &ApplyResult{}
+	var blank bool
+	if len(ss) == 0 {
+		if !d.allowEmpty {
+			return nil, ErrNoSlides
+		}
+		if d.managedStart == 0 {
+			// A presentation cannot be emptied, so clear it down to one blank slide.
+			blank = true
+			ss = Slides{{}}
+			pages = []int{1}
+		}
+	}
 	if slices.ContainsFunc(pages, func(page int) bool {
 		return page < 1 || page > len(ss)
 	}) {
-		return fmt.Errorf("invalid page number in pages: %v", pages)
+		return nil, fmt.Errorf("invalid page number in pages: %v", pages)
+	}
+	if d.wideTablePolicy == WideTablePolicySplit {
+		ss, pages = splitWideTables(ss, pages, d.wideTableMaxColumns)
 	}
 
 	if err := d.refresh(ctx); err != nil {
-		return fmt.Errorf("failed to refresh presentation: %w", err)
+		return nil, fmt.Errorf("failed to refresh presentation: %w", err)
+	}
+	if blank {
+		ss[0].Layout = d.blankLayout()
+	}
+	baseRevisionID := d.presentation.RevisionId
+	hashes := slideHashes(ss)
+	appliedPages := pages
+	if unchanged := d.unchangedPages(pages, hashes); len(unchanged) > 0 {
+		d.logger.Info("skip pages unchanged since the last apply", slog.Any("pages", unchanged))
+		pages = slices.DeleteFunc(slices.Clone(pages), func(page int) bool {
+			return slices.Contains(unchanged, page)
+		})
 	}
 
-	// Validate layouts before processing
+	// Validate layouts before processing, so that no images are uploaded for an apply that cannot succeed
 	if err := d.validateLayouts(ss); err != nil {
-		return fmt.Errorf("layout validation failed: %w", err)
+		return nil, fmt.Errorf("layout validation failed: %w", err)
+	}
+	for _, slide := range ss {
+		if err := validateAccent(slide.Accent); err != nil {
+			return nil, err
+		}
+		for _, t := range slide.Tables {
+			if err := validateTableMerges(t); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	layoutObjectIdMap := map[string]*slides.Page{}
 	for _, l := range d.presentation.Layouts {
 		layoutObjectIdMap[l.ObjectId] = l
 	}
-	beforeLen := len(d.presentation.Slides)
+	start := d.managedStart
+	if start > len(d.presentation.Slides) {
+		return nil, fmt.Errorf("managed range start %d is out of range: the presentation has %d slides", start, len(d.presentation.Slides))
+	}
+	beforeLen := len(d.presentation.Slides) - start
 
 	d.logger.Debug("starting to apply pages",
-		slog.Int("before_len", beforeLen), slog.Int("after_len", len(ss)), slog.Any("pages", pages))
+		slog.Int("before_len", beforeLen), slog.Int("after_len", len(ss)), slog.Any("pages", pages), slog.Int("managed_start", start))
 
 	before := make(Slides, beforeLen)
 	after := make(Slides, beforeLen)
-	for i, p := range d.presentation.Slides {
+	for i, p := range d.presentation.Slides[start:] {
 		slide := convertToSlide(p, layoutObjectIdMap)
 		before[i] = slide
 		after[i] = slide
@@ -87,23 +154,54 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 			after = append(after, slide)
 		}
 	}
+	if d.preserveEdited {
+		for i, slide := range before {
+			if i < len(after) && isEditedSlide(slide) {
+				d.logger.Info("skip applying page. because it is marked as edited", slog.Int("index", start+i))
+				// Freeze the current slide so that no actions are generated for it.
+				preserved := copySlide(slide)
+				preserved.Freeze = true
+				after[i] = preserved
+			}
+		}
+	}
 	if len(after) > len(ss) {
 		after = after[:len(ss)]
 	}
 
+	if err := d.readImageSizes(before, after); err != nil {
+		return nil, fmt.Errorf("failed to read image sizes: %w", err)
+	}
 	actions, err := generateActions(before, after)
 	if err != nil {
-		return fmt.Errorf("failed to generate actions: %w", err)
+		return nil, fmt.Errorf("failed to generate actions: %w", err)
+	}
+	// Actions are generated for the managed range, so shift them to the indices in the presentation.
+	for _, action := range actions {
+		action.index += start
+		if action.actionType == actionTypeMove {
+			action.moveToIndex += start
+		}
+	}
+
+	if d.dryRun {
+		return d.planActions(actions), nil
 	}
 
 	// Pre-fetch current images in parallel for only the slides that will be updated
 	currentImages, err := d.preloadCurrentImages(ctx, actions)
+	var preloadErr error
 	if err != nil {
-		return fmt.Errorf("failed to preload current images: %w", err)
+		if currentImages == nil {
+			return nil, fmt.Errorf("failed to preload current images: %w", err)
+		}
+		// Images that failed to be preloaded are replaced, and the error is returned after the apply.
+		preloadErr = fmt.Errorf("failed to preload current images: %w", err)
 	}
 
 	// Start uploading new images in parallel (don't wait for completion)
-	uploadedCh := d.startUploadingImages(ctx, actions, currentImages)
+	stats := &imageStats{}
+	uploadedCh := d.startUploadingImages(ctx, actions, currentImages, stats)
 	defer func() {
 		// Clean up uploaded images in parallel
 		if cleanupErr := d.cleanupUploadedImages(ctx, uploadedCh); cleanupErr != nil {
@@ -113,6 +211,8 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 				d.logger.Error("failed to cleanup uploaded images", slog.Any("error", cleanupErr))
 			}
 		}
+		result.ImagesUploaded = int(stats.uploaded.Load())
+		result.ImagesReused = int(stats.reused.Load())
 	}()
 
 	d.logger.Info("applying actions", slog.Any("actions", toActionLogs(actions)))
@@ -131,13 +231,15 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 		for i, l := range layoutsForAppendPages {
 			layout, ok := layoutMap[l]
 			if !ok {
-				return fmt.Errorf("layout not found: %q", l)
+				return nil, fmt.Errorf("layout not found: %q", l)
 			}
 			layoutObjectIDs[i] = layout.ObjectId
 		}
-		// prepare pages for appending new slides in advance
+		// prepare pages for appending new slides in advance.
+		// The presentation is refreshed after the pages are created, so images are placed
+		// with the geometry of the placeholders of the created pages, while uploads keep running.
 		if err := d.preparePages(ctx, currentSlidesLen, layoutObjectIDs); err != nil {
-			return fmt.Errorf("failed to create pages: %w", err)
+			return nil, fmt.Errorf("failed to create pages: %w", err)
 		}
 	}
 
@@ -155,12 +257,12 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 			len(applyRequests) > 0 {
 
 			if err := d.batchUpdate(ctx, applyRequests); err != nil {
-				return fmt.Errorf("failed to apply pages in batches: %w", err)
+				return nil, fmt.Errorf("failed to apply pages in batches: %w", err)
 			}
 
 			// Fill table content for updated/appended slides
 			if err := d.fillTableContentForActions(ctx, actions); err != nil {
-				return err
+				return nil, err
 			}
 			if appendingCount > 0 {
 				d.logger.Info("appended pages", slog.Int("count", appendingCount))
@@ -175,8 +277,8 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 		if action.actionType != actionTypeDelete && len(deletingIndices) > 0 {
 			// The indexes of consecutive delete actions are sorted in descending order,
 			// so no position adjustment is necessary.
-			if err := d.DeletePages(ctx, deletingIndices); err != nil {
-				return fmt.Errorf("failed to delete pages: %w", err)
+			if err := d.deletePages(ctx, deletingIndices); err != nil {
+				return nil, fmt.Errorf("failed to delete pages: %w", err)
 			}
 			deletingIndices = nil
 		}
@@ -184,29 +286,68 @@ func (d *Deck) ApplyPages(ctx context.Context, ss Slides, pages []int) (err erro
 		case actionTypeAppend:
 			d.logger.Info("preparing to append new page")
 			if reqs, err := d.prepareToApplyPage(ctx, nextAppendingIndex, action.slide, nil); err != nil {
-				return fmt.Errorf("failed to apply page: %w", err)
+				return nil, fmt.Errorf("failed to apply page: %w", err)
 			} else if len(reqs) > 0 {
 				applyRequests = append(applyRequests, reqs...)
 			}
+			result.add(SlideOutcomeCreated, nextAppendingIndex, action.slide)
 			appendingCount++
 			nextAppendingIndex++
 		case actionTypeUpdate:
 			d.logger.Info("preparing to apply page", slog.Int("index", action.index))
 			if reqs, err := d.prepareToApplyPage(ctx, action.index, action.slide, currentImages[action.index]); err != nil {
-				return fmt.Errorf("failed to apply page: %w", err)
+				return nil, fmt.Errorf("failed to apply page: %w", err)
 			} else if len(reqs) > 0 {
 				applyRequests = append(applyRequests, reqs...)
+				d.stats().slidesUpdated.Add(1)
 			}
+			result.add(SlideOutcomeUpdated, action.index, action.slide)
 			applyingCount++
 		case actionTypeMove:
-			if err := d.MovePage(ctx, action.index, action.moveToIndex); err != nil {
-				return fmt.Errorf("failed to move page: %w", err)
+			if err := d.movePage(ctx, action.index, action.moveToIndex); err != nil {
+				return nil, fmt.Errorf("failed to move page: %w", err)
 			}
+			result.add(SlideOutcomeMoved, action.moveToIndex, action.slide)
 		case actionTypeDelete:
 			deletingIndices = append(deletingIndices, action.index)
+			result.add(SlideOutcomeDeleted, action.index, action.slide)
 		}
 	}
-	return d.refresh(ctx)
+	if err := d.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if err := d.saveApplyState(baseRevisionID, appliedPages, hashes); err != nil {
+		d.logger.Warn("failed to save the state of apply", slog.Any("error", err))
+	}
+	return result, preloadErr
+}
+
+// planActions returns the result of the actions without applying them.
+func (d *Deck) planActions(actions []*action) *ApplyResult {
+	result := &ApplyResult{DryRun: true}
+	nextAppendingIndex := len(d.presentation.Slides)
+	for _, action := range actions {
+		var titles []string
+		if action.slide != nil {
+			titles = action.slide.Titles
+		}
+		switch action.actionType {
+		case actionTypeAppend:
+			result.add(SlideOutcomeCreated, nextAppendingIndex, action.slide)
+			d.logger.Info("plan to append page", slog.Int("index", nextAppendingIndex), slog.Any("titles", titles))
+			nextAppendingIndex++
+		case actionTypeUpdate:
+			result.add(SlideOutcomeUpdated, action.index, action.slide)
+			d.logger.Info("plan to apply page", slog.Int("index", action.index), slog.Any("titles", titles))
+		case actionTypeMove:
+			result.add(SlideOutcomeMoved, action.moveToIndex, action.slide)
+			d.logger.Info("plan to move page", slog.Int("from_index", action.index), slog.Int("to_index", action.moveToIndex), slog.Any("titles", titles))
+		case actionTypeDelete:
+			result.add(SlideOutcomeDeleted, action.index, action.slide)
+			d.logger.Info("plan to delete page", slog.Int("index", action.index), slog.Any("titles", titles))
+		}
+	}
+	return result
 }
 
 type actionLog struct {
@@ -232,25 +373,38 @@ func toActionLogs(actions []*action) []*actionLog {
 var apiErrReg = regexp.MustCompile(`googleapi: Error 400: Invalid requests\[([0-9]+)\]\.`)
 
 func (d *Deck) batchUpdate(ctx context.Context, requests []*slides.Request) error {
+	_, err := d.batchUpdateWithReplies(ctx, requests)
+	return err
+}
+
+// batchUpdateWithReplies is like batchUpdate but also returns the replies to the requests in order.
+// It returns no replies in dry-run mode.
+func (d *Deck) batchUpdateWithReplies(ctx context.Context, requests []*slides.Request) ([]*slides.Response, error) {
+	if d.dryRun {
+		for _, req := range requests {
+			d.logger.Info("skip request because of dry-run", slog.Any("request", req))
+		}
+		return nil, nil
+	}
 	d.logger.Info("batch updating presentation request", slog.Int("count", len(requests)))
 	d.fresh = false
-	// Although there is no explicit request limit specified in the Google Slides API specifications,
-	// we will set an upper limit as a precaution.
-	// After testing several times, it handles around 1,000 requests without any issues so that we will
-	// set the upper limit at that point for now.
-	// This limit corresponds to approximately 100 pages of presentation requests.
-	const reqCountLimit = 1000
-	reqLen := len(requests)
-	var groups [][]*slides.Request
-	for i := 0; i < reqLen; i += reqCountLimit {
-		end := min(i+reqCountLimit, reqLen)
-		groups = append(groups, requests[i:end])
+	d.title = ""
+	groups := chunkRequests(requests, d.batchSizeOrDefault())
+	if len(groups) > 1 {
+		d.logger.Info("batch updating presentation in chunks", slog.Int("chunks", len(groups)))
 	}
+	p := d.newProgress(ProgressPhaseBatching, len(groups))
+	var replies []*slides.Response
 	for _, requests := range groups {
 		req := &slides.BatchUpdatePresentationRequest{
 			Requests: requests,
 		}
-		if _, err := d.srv.Presentations.BatchUpdate(d.id, req).Context(ctx).Do(); err != nil {
+		var res *slides.BatchUpdatePresentationResponse
+		if err := d.call(ctx, "batch update", func(ctx context.Context) (err error) {
+			res, err = d.srv.Presentations.BatchUpdate(d.id, req).Context(ctx).Do()
+			return err
+		}); err != nil {
+			d.stats().addError(ErrorTypeBatchUpdate)
 			errMsg := err.Error()
 			if matches := apiErrReg.FindStringSubmatch(errMsg); len(matches) == 2 {
 				errIndex, err := strconv.Atoi(matches[1])
@@ -259,10 +413,12 @@ func (d *Deck) batchUpdate(ctx context.Context, requests []*slides.Request) erro
 					d.logger.Debug("invalid request found in batchUpdate", slog.Any("request", errReq), slog.Int("index", errIndex))
 				}
 			}
-			return fmt.Errorf("failed to batch update presentation: %w", err)
+			return nil, fmt.Errorf("failed to batch update presentation: %w", err)
 		}
+		replies = append(replies, res.Replies...)
+		p.done(-1)
 	}
-	return nil
+	return replies, nil
 }
 
 func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide, preloaded *currentImageData) (
@@ -305,6 +461,7 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		currentBlockquoteIDs      []string
 		currentTextBoxObjectIDMap = map[*textBox]string{} // key: *textBox, value: objectID
 		currentTables             []*slides.PageElement
+		currentTexts              = map[string]*slides.TextContent{} // key: objectID of placeholder
 	)
 
 	// Use preloaded image data if available, otherwise fetch on demand
@@ -325,6 +482,7 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 					y:        element.Transform.TranslateY,
 				})
 				requests = append(requests, d.clearPlaceholderRequests(element)...)
+				currentTexts[element.ObjectId] = element.Shape.Text
 			case "SUBTITLE":
 				subtitles = append(subtitles, placeholder{
 					objectID: element.ObjectId,
@@ -332,6 +490,7 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 					y:        element.Transform.TranslateY,
 				})
 				requests = append(requests, d.clearPlaceholderRequests(element)...)
+				currentTexts[element.ObjectId] = element.Shape.Text
 			case "BODY":
 				bodies = append(bodies, placeholder{
 					objectID: element.ObjectId,
@@ -339,12 +498,14 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 					y:        element.Transform.TranslateY,
 				})
 				requests = append(requests, d.clearPlaceholderRequests(element)...)
+				currentTexts[element.ObjectId] = element.Shape.Text
 			}
 		case element.Image != nil && element.Image.Placeholder != nil:
 			imagePlaceholders = append(imagePlaceholders, placeholder{
 				objectID: element.ObjectId,
 				x:        element.Transform.TranslateX,
 				y:        element.Transform.TranslateY,
+				name:     placeholderName(element, layout),
 			})
 		case element.Image != nil && preloaded == nil:
 			// Only fetch images on demand if preloaded data is not available
@@ -353,16 +514,18 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 				err   error
 			)
 			if element.Description == descriptionImageFromMarkdown {
-				image, err = NewImageFromMarkdown(element.Image.ContentUrl)
+				image, err = d.fetchImage(element.Image.ContentUrl, true)
 				if err != nil {
 					return nil, fmt.Errorf("failed to create image from code block %s: %w", element.Image.ContentUrl, err)
 				}
 			} else {
-				image, err = NewImage(element.Image.ContentUrl)
+				image, err = d.fetchImage(element.Image.ContentUrl, false)
 				if err != nil {
 					return nil, fmt.Errorf("failed to create image from %s: %w", element.Image.ContentUrl, err)
 				}
 			}
+			image.link = imageLink(element.Image)
+			image.alt = markdownImageAlt(element)
 			currentImages = append(currentImages, image)
 			currentImageObjectIDMap[image] = element.ObjectId
 		case element.Shape != nil && element.Shape.ShapeType == "TEXT_BOX" && element.Shape.Text != nil:
@@ -379,18 +542,14 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 			currentTables = append(currentTables, element)
 		}
 	}
-	var speakerNotesID string
-	for _, element := range currentSlide.SlideProperties.NotesPage.PageElements {
-		if element.Shape != nil && element.Shape.Placeholder != nil {
-			if element.Shape.Placeholder.Type == "BODY" {
-				speakerNotesID = element.ObjectId
-				requests = append(requests, d.clearPlaceholderRequests(element)...)
-			}
-		}
-	}
-	if speakerNotesID == "" {
+	notesElement := speakerNotesElement(currentSlide)
+	if notesElement == nil {
 		return nil, fmt.Errorf("speaker notes not found")
 	}
+	speakerNotesID := notesElement.ObjectId
+	// Clear the existing notes to avoid duplication on re-apply
+	requests = append(requests, d.clearPlaceholderRequests(notesElement)...)
+	currentTexts[speakerNotesID] = notesElement.Shape.Text
 
 	// set titles
 	sort.Slice(titles, func(i, j int) bool {
@@ -399,6 +558,7 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		}
 		return titles[i].y < titles[j].y
 	})
+	var accentTitleIDs []string
 	for i, b := range slide.TitleBodies {
 		if len(titles) <= i {
 			break
@@ -409,7 +569,11 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		}
 		requests = append(requests, reqs...)
 		requests = append(requests, styleReqs...)
+		if len(reqs) > 0 {
+			accentTitleIDs = append(accentTitleIDs, titles[i].objectID)
+		}
 	}
+	requests = append(requests, accentRequests(currentSlide, accentTitleIDs, slide.Accent)...)
 
 	// set subtitles
 	sort.Slice(subtitles, func(i, j int) bool {
@@ -464,10 +628,34 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		}
 		return imagePlaceholders[i].y < imagePlaceholders[j].y
 	})
+	replacedImageElements := staleImageElements(currentSlide, slide.Images, currentImages, currentImageObjectIDMap)
+	slots := assignImagePlaceholders(slide.Images, imagePlaceholders)
 	for i, image := range slide.Images {
-		if slices.ContainsFunc(currentImages, func(currentImage *Image) bool {
+		if j := slices.IndexFunc(currentImages, func(currentImage *Image) bool {
 			return currentImage.Equivalent(image)
-		}) {
+		}); j >= 0 {
+			if currentImage := currentImages[j]; currentImage.fromMarkdown && currentImage.alt != image.alt {
+				if imageObjectID, ok := currentImageObjectIDMap[currentImage]; ok {
+					requests = append(requests, &slides.Request{
+						UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+							ObjectId:    imageObjectID,
+							Title:       image.alt,
+							Description: descriptionImageFromMarkdown,
+						},
+					})
+				}
+			}
+			size, err := d.imageSize(image)
+			if err != nil {
+				return nil, err
+			}
+			if size != nil {
+				if element := pageElementByID(currentSlide, currentImageObjectIDMap[currentImages[j]]); element != nil {
+					if req := resizeImageRequest(element, size); req != nil {
+						requests = append(requests, req)
+					}
+				}
+			}
 			continue
 		}
 
@@ -480,14 +668,14 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 			return nil, fmt.Errorf("image not uploaded or webContentLink is empty")
 		}
 		var imageObjectID string
-		if len(imagePlaceholders) > i {
+		if slots[i] != nil {
 			imageReplaceMethod := "CENTER_CROP"
 			if info.codeBlock {
 				// In the case of code blocks, it is important that the entire image can be seen
 				// without being cropped, so switch the replace method.
 				imageReplaceMethod = "CENTER_INSIDE"
 			}
-			imageObjectID = imagePlaceholders[i].objectID
+			imageObjectID = slots[i].objectID
 			requests = append(requests, &slides.Request{
 				ReplaceImage: &slides.ReplaceImageRequest{
 					ImageObjectId:      imageObjectID,
@@ -511,6 +699,36 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 				},
 				Url: info.url,
 			}
+			if len(replacedImageElements) > 0 {
+				// The new image inherits the position and size of the image it replaces,
+				// so that carefully positioned images stay stable across edits.
+				replaced := replacedImageElements[0]
+				replacedImageElements = replacedImageElements[1:]
+				imageReq.ElementProperties.Size = replaced.Size
+				imageReq.ElementProperties.Transform = replaced.Transform
+			} else if d.imageAnchor != "" && len(slide.Images) == 1 {
+				w, h, err := imageDimensions(image)
+				if err != nil {
+					return nil, err
+				}
+				imageReq.ElementProperties.Size, imageReq.ElementProperties.Transform = anchorImage(d.imageContentArea(), w, h, d.imageAnchor)
+			}
+			size, err := d.imageSize(image)
+			if err != nil {
+				return nil, err
+			}
+			if size != nil {
+				// The size given in markdown takes precedence, keeping the position.
+				t := imageReq.ElementProperties.Transform
+				imageReq.ElementProperties.Size = size
+				imageReq.ElementProperties.Transform = &slides.AffineTransform{
+					ScaleX:     1.0,
+					ScaleY:     1.0,
+					TranslateX: t.TranslateX,
+					TranslateY: t.TranslateY,
+					Unit:       "EMU",
+				}
+			}
 			requests = append(requests, &slides.Request{
 				CreateImage: imageReq,
 			})
@@ -532,6 +750,7 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 			requests = append(requests, &slides.Request{
 				UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
 					ObjectId:    imageObjectID,
+					Title:       image.alt,
 					Description: descriptionImageFromMarkdown,
 				},
 			})
@@ -602,7 +821,34 @@ func (d *Deck) prepareToApplyPage(ctx context.Context, index int, slide *Slide,
 		})
 	}
 
-	return requests, nil
+	// Rewrite only the changed part of the text of placeholders
+	return minimizeTextRequests(requests, currentTexts), nil
+}
+
+// staleImageElements returns the page elements of the current images via markdown that do not match
+// any of the images to be applied, in the order of the current images.
+// These are the images that will be pruned and replaced by new images.
+func staleImageElements(currentSlide *slides.Page, images, currentImages []*Image, currentImageObjectIDMap map[*Image]string) []*slides.PageElement {
+	elementMap := map[string]*slides.PageElement{}
+	for _, element := range currentSlide.PageElements {
+		if element.Image != nil {
+			elementMap[element.ObjectId] = element
+		}
+	}
+	var elements []*slides.PageElement
+	for _, currentImage := range currentImages {
+		if !currentImage.fromMarkdown || slices.ContainsFunc(images, func(image *Image) bool {
+			return currentImage.Equivalent(image)
+		}) {
+			continue
+		}
+		element, ok := elementMap[currentImageObjectIDMap[currentImage]]
+		if !ok {
+			continue
+		}
+		elements = append(elements, element)
+	}
+	return elements
 }
 
 func (d *Deck) applyParagraphsRequests(objectID string, paragraphs []*Paragraph) (reqs []*slides.Request, styleReqs []*slides.Request, err error) {
@@ -615,7 +861,9 @@ func (d *Deck) applyParagraphsRequests(objectID string, paragraphs []*Paragraph)
 	text := ""
 	bulletStartIndex := int64(0) // reset per body
 	bulletEndIndex := int64(0)   // reset per body
-	currentBullet := BulletNone
+	// listBullet is the bullet of the top level of the current list.
+	// Nested lists of any depth and type belong to the list, and their nesting levels are given by leading tabs.
+	listBullet := BulletNone
 	for j, paragraph := range paragraphs {
 		plen := 0
 		if paragraph.Bullet != BulletNone {
@@ -656,7 +904,8 @@ func (d *Deck) applyParagraphsRequests(objectID string, paragraphs []*Paragraph)
 		}
 
 		if paragraph.Bullet != BulletNone {
-			if paragraph.Nesting == 0 && currentBullet != paragraph.Bullet {
+			if listBullet == BulletNone || (paragraph.Nesting == 0 && listBullet != paragraph.Bullet) {
+				listBullet = paragraph.Bullet
 				bulletStartIndex = count
 				bulletEndIndex = count
 				bulletRanges[int(bulletStartIndex)] = &bulletRange{
@@ -667,8 +916,9 @@ func (d *Deck) applyParagraphsRequests(objectID string, paragraphs []*Paragraph)
 			}
 			bulletEndIndex += int64(plen)
 			bulletRanges[int(bulletStartIndex)].end = bulletEndIndex
+		} else {
+			listBullet = BulletNone
 		}
-		currentBullet = paragraph.Bullet
 		count += int64(plen)
 	}
 
@@ -824,6 +1074,7 @@ func (d *Deck) updateLayout(ctx context.Context, index int, slide *Slide) (err e
 				reqs = append(reqs, &slides.Request{
 					UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
 						ObjectId:    imageObjectID,
+						Title:       element.Title,
 						Description: descriptionImageFromMarkdown,
 					},
 				})
@@ -990,7 +1241,7 @@ func (d *Deck) updateLayout(ctx context.Context, index int, slide *Slide) (err e
 			return fmt.Errorf("failed to copy images or insert text: %w", err)
 		}
 	}
-	if err := d.DeletePages(ctx, []int{index}); err != nil {
+	if err := d.deletePages(ctx, []int{index}); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,531 @@
+package deck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestPrepareToApplyPageReplacedImageRetainsTransform(t *testing.T) {
+	oldImage, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newImage, err := NewImageFromMarkdown("testdata/test.jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newImage.webContentLink = "https://example.com/new.jpeg"
+
+	size := &slides.Size{
+		Width:  &slides.Dimension{Magnitude: 3000000, Unit: "EMU"},
+		Height: &slides.Dimension{Magnitude: 2000000, Unit: "EMU"},
+	}
+	transform := &slides.AffineTransform{
+		ScaleX:     1.5,
+		ScaleY:     1.5,
+		TranslateX: 1234567,
+		TranslateY: 7654321,
+		Unit:       "EMU",
+	}
+	d := &Deck{
+		logger: slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		presentation: &slides.Presentation{
+			Layouts: []*slides.Page{{
+				ObjectId:         "layout-1",
+				LayoutProperties: &slides.LayoutProperties{DisplayName: "Title and Content"},
+			}},
+			Slides: []*slides.Page{{
+				ObjectId: "slide-1",
+				SlideProperties: &slides.SlideProperties{
+					LayoutObjectId: "layout-1",
+					NotesPage: &slides.Page{
+						PageElements: []*slides.PageElement{{
+							ObjectId: "notes-1",
+							Shape: &slides.Shape{
+								Placeholder: &slides.Placeholder{Type: "BODY"},
+							},
+						}},
+					},
+				},
+				PageElements: []*slides.PageElement{{
+					ObjectId:    "old-image",
+					Description: descriptionImageFromMarkdown,
+					Size:        size,
+					Transform:   transform,
+					Image:       &slides.Image{ContentUrl: "https://example.com/old.png"},
+				}},
+			}},
+		},
+	}
+	preloaded := &currentImageData{
+		currentImages:           []*Image{oldImage},
+		currentImageObjectIDMap: map[*Image]string{oldImage: "old-image"},
+	}
+	slide := &Slide{
+		Layout: "Title and Content",
+		Images: []*Image{newImage},
+	}
+
+	reqs, err := d.prepareToApplyPage(context.Background(), 0, slide, preloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		created *slides.CreateImageRequest
+		deleted bool
+	)
+	for _, r := range reqs {
+		if r.CreateImage != nil {
+			created = r.CreateImage
+		}
+		if r.DeleteObject != nil && r.DeleteObject.ObjectId == "old-image" {
+			deleted = true
+		}
+	}
+	if created == nil {
+		t.Fatal("CreateImage request not found")
+	}
+	if created.Url != "https://example.com/new.jpeg" {
+		t.Errorf("got url %q, want %q", created.Url, "https://example.com/new.jpeg")
+	}
+	if diff := cmp.Diff(transform, created.ElementProperties.Transform); diff != "" {
+		t.Errorf("transform mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(size, created.ElementProperties.Size); diff != "" {
+		t.Errorf("size mismatch (-want +got):\n%s", diff)
+	}
+	if !deleted {
+		t.Error("replaced image should be deleted")
+	}
+}
+
+func TestApplyWithManagedRange(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("intro", "a", "b")}
+	d := newFakeDeck(t, s, WithManagedRange(1))
+
+	ss := Slides{{
+		Layout:      "Title and Content",
+		Titles:      []string{"a2"},
+		TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "a2"}}}}}},
+	}, {
+		Layout:      "Title and Content",
+		Titles:      []string{"b"},
+		TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "b"}}}}}},
+	}}
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.batchUpdates) == 0 {
+		t.Fatal("managed slides should be updated")
+	}
+	var updated bool
+	for _, req := range s.batchUpdates {
+		b, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(b), `"slide-0`) {
+			t.Errorf("intro slide should be untouched: %s", b)
+		}
+		if strings.Contains(string(b), `"slide-1-title"`) {
+			updated = true
+		}
+	}
+	if !updated {
+		t.Error("first managed slide should be updated")
+	}
+	if got := d.Metrics().SlidesUpdated; got != 1 {
+		t.Errorf("got %d updated slides, want 1", got)
+	}
+}
+
+func TestApplyWithManagedRangeOutOfRange(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("intro")}
+	d := newFakeDeck(t, s, WithManagedRange(2))
+	if _, err := d.Apply(t.Context(), Slides{{Layout: "Title and Content"}}); err == nil {
+		t.Error("expected error but got none")
+	}
+	if len(s.batchUpdates) != 0 {
+		t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+	}
+}
+
+func TestApplyResult(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a", "b", "c")}
+	d := newFakeDeck(t, s)
+
+	ss := Slides{{
+		Layout:      "Title and Content",
+		Titles:      []string{"a"},
+		TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "a"}}}}}},
+		Bodies:      []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "changed"}}}}}},
+	}}
+	result, err := d.Apply(t.Context(), ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ApplyResult{
+		Updated: 1,
+		Deleted: 2,
+		Slides: []*SlideResult{
+			{Outcome: SlideOutcomeUpdated, Index: 0, Titles: []string{"a"}},
+			{Outcome: SlideOutcomeDeleted, Index: 2, Titles: []string{"c"}},
+			{Outcome: SlideOutcomeDeleted, Index: 1, Titles: []string{"b"}},
+		},
+	}
+	if diff := cmp.Diff(want, result); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+	if got := result.Changed(); got != 3 {
+		t.Errorf("got %d changed slides, want 3", got)
+	}
+}
+
+func TestApplyDryRun(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a", "b")}
+	d := newFakeDeck(t, s, WithDryRun(true))
+
+	ss := Slides{{
+		Layout:      "Title and Content",
+		Titles:      []string{"a"},
+		TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "a"}}}}}},
+		Bodies:      []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "changed"}}}}}},
+	}}
+	result, err := d.Apply(t.Context(), ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.batchUpdates) != 0 {
+		t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+	}
+	if !result.DryRun || result.Updated != 1 || result.Deleted != 1 {
+		t.Errorf("unexpected plan: %+v", result)
+	}
+	want := "~ updated page 1: a\n- deleted page 2: b\n0 created, 1 updated, 0 moved, 1 deleted\n"
+	if got := result.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if err := d.UpdateTitle(t.Context(), "title"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyInvalidLayoutDoesNotUpload(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	storage := &countingStorage{}
+	d := newFakeDeck(t, s, WithStorage(storage))
+	image, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = d.Apply(t.Context(), Slides{{Layout: "not found", Images: []*Image{image}}})
+	var layoutErr *LayoutNotFoundError
+	if !errors.As(err, &layoutErr) {
+		t.Fatalf("got %v, want LayoutNotFoundError", err)
+	}
+	if !slices.Equal(layoutErr.Layouts, []string{"not found"}) {
+		t.Errorf("got %q, want %q", layoutErr.Layouts, []string{"not found"})
+	}
+	if storage.uploads != 0 {
+		t.Errorf("got %d uploads, want 0", storage.uploads)
+	}
+	if len(s.batchUpdates) != 0 {
+		t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+	}
+}
+
+func TestApplyEmpty(t *testing.T) {
+	t.Run("rejected by default", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("a", "b")}
+		d := newFakeDeck(t, s)
+		if _, err := d.Apply(t.Context(), Slides{}); !errors.Is(err, ErrNoSlides) {
+			t.Fatalf("got %v, want ErrNoSlides", err)
+		}
+		if len(s.batchUpdates) != 0 {
+			t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("a", "b")}
+		s.presentation.Layouts = append(s.presentation.Layouts, &slides.Page{
+			ObjectId:         "layout-blank",
+			LayoutProperties: &slides.LayoutProperties{Name: "BLANK", DisplayName: "Blank"},
+		})
+		// Reflect the created and deleted slides on the presentation, as the Slides API does.
+		s.batchUpdateHook = func(req *slides.BatchUpdatePresentationRequest) error {
+			for _, r := range req.Requests {
+				switch {
+				case r.CreateSlide != nil:
+					page := newFakePresentation("").Slides[0]
+					page.ObjectId = r.CreateSlide.ObjectId
+					page.SlideProperties.LayoutObjectId = r.CreateSlide.SlideLayoutReference.LayoutId
+					s.presentation.Slides = slices.Insert(s.presentation.Slides, int(r.CreateSlide.InsertionIndex), page)
+				case r.DeleteObject != nil:
+					s.presentation.Slides = slices.DeleteFunc(s.presentation.Slides, func(p *slides.Page) bool {
+						return p.ObjectId == r.DeleteObject.ObjectId
+					})
+				}
+			}
+			return nil
+		}
+		d := newFakeDeck(t, s, WithAllowEmpty(true))
+		if _, err := d.Apply(t.Context(), Slides{}); err != nil {
+			t.Fatal(err)
+		}
+		var layouts []string
+		for _, p := range s.presentation.Slides {
+			layouts = append(layouts, p.SlideProperties.LayoutObjectId)
+		}
+		if want := []string{"layout-blank"}; !slices.Equal(layouts, want) {
+			t.Errorf("got slides with layouts %q, want %q", layouts, want)
+		}
+	})
+}
+
+func TestApplyPlacesImagesAfterPageCreation(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	s.batchUpdateHook = func(req *slides.BatchUpdatePresentationRequest) error {
+		for _, r := range req.Requests {
+			if r.CreateSlide == nil {
+				continue
+			}
+			// The image placeholder exists only after the page is created from the layout.
+			page := newFakePresentation("").Slides[0]
+			page.ObjectId = r.CreateSlide.ObjectId
+			page.PageElements = append(page.PageElements, &slides.PageElement{
+				ObjectId:  r.CreateSlide.ObjectId + "-image",
+				Transform: &slides.AffineTransform{TranslateX: 100, TranslateY: 200},
+				Image:     &slides.Image{Placeholder: &slides.Placeholder{Type: "PICTURE"}},
+			})
+			s.presentation.Slides = append(s.presentation.Slides, page)
+		}
+		return nil
+	}
+	d := newFakeDeck(t, s, WithStorage(&countingStorage{}))
+	image, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss := Slides{
+		{Layout: "Title and Content", Titles: []string{"a"}},
+		{Layout: "Title and Content", Titles: []string{"b"}, Images: []*Image{image}},
+	}
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+
+	var replaced []string
+	for _, req := range s.batchUpdates {
+		for _, r := range req.Requests {
+			if r.CreateImage != nil {
+				t.Errorf("image should be placed in the placeholder of the created page, but created at %+v", r.CreateImage.ElementProperties.Transform)
+			}
+			if r.ReplaceImage != nil {
+				replaced = append(replaced, r.ReplaceImage.ImageObjectId)
+			}
+		}
+	}
+	if len(replaced) != 1 || !strings.HasSuffix(replaced[0], "-image") {
+		t.Errorf("got replaced images %q, want the placeholder of the created page", replaced)
+	}
+}
+
+func TestPrepareToApplyPageKeepsLinkedImage(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(ts.Close)
+	image, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	image.SetLink("https://example.com")
+
+	p := newFakePresentation("a")
+	p.Slides[0].PageElements = append(p.Slides[0].PageElements, &slides.PageElement{
+		ObjectId:    "linked-image",
+		Description: descriptionImageFromMarkdown,
+		Transform:   &slides.AffineTransform{},
+		Image: &slides.Image{
+			ContentUrl:      ts.URL + "/test.png",
+			ImageProperties: &slides.ImageProperties{Link: &slides.Link{Url: "https://example.com"}},
+		},
+	})
+	d := &Deck{
+		logger:       slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		presentation: p,
+	}
+	slide := &Slide{
+		Layout: "Title and Content",
+		Images: []*Image{image},
+	}
+
+	// Without preloaded images, the current images are fetched on demand.
+	reqs, err := d.prepareToApplyPage(t.Context(), 0, slide, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range reqs {
+		if r.CreateImage != nil || r.DeleteObject != nil || r.UpdateImageProperties != nil {
+			t.Errorf("unchanged linked image should be kept, but got %+v", r)
+		}
+	}
+}
+
+func TestApplySetsImageAltText(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	d := newFakeDeck(t, s, WithStorage(&countingStorage{}))
+	image, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	image.SetAlt("Monthly sales chart")
+
+	ss := Slides{
+		{Layout: "Title and Content", Titles: []string{"a"}, Images: []*Image{image}},
+	}
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*slides.UpdatePageElementAltTextRequest
+	for _, req := range s.batchUpdates {
+		for _, r := range req.Requests {
+			if r.UpdatePageElementAltText != nil {
+				got = append(got, r.UpdatePageElementAltText)
+			}
+		}
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d alt text updates, want 1", len(got))
+	}
+	if got[0].Title != "Monthly sales chart" {
+		t.Errorf("got alt text title %q, want %q", got[0].Title, "Monthly sales chart")
+	}
+	if got[0].Description != descriptionImageFromMarkdown {
+		t.Errorf("got alt text description %q, want the markdown marker", got[0].Description)
+	}
+}
+
+func TestApplyUpdatesImageAltText(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(ts.Close)
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	s.presentation.Slides[0].PageElements = append(s.presentation.Slides[0].PageElements, &slides.PageElement{
+		ObjectId:    "image",
+		Title:       "Old chart",
+		Description: descriptionImageFromMarkdown,
+		Transform:   &slides.AffineTransform{},
+		Image:       &slides.Image{ContentUrl: ts.URL + "/test.png"},
+	})
+	d := newFakeDeck(t, s, WithStorage(&countingStorage{}))
+	image, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	image.SetAlt("New chart")
+
+	ss := Slides{{Layout: "Title and Content", Titles: []string{"a"}, Images: []*Image{image}}}
+	result, err := d.Apply(t.Context(), ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 {
+		t.Errorf("got %d updated slides, want 1", result.Updated)
+	}
+	var got []string
+	for _, req := range s.batchUpdates {
+		for _, r := range req.Requests {
+			if r.UpdatePageElementAltText != nil && r.UpdatePageElementAltText.ObjectId == "image" {
+				got = append(got, r.UpdatePageElementAltText.Title)
+			}
+		}
+	}
+	if len(got) != 1 || got[0] != "New chart" {
+		t.Errorf("got alt text updates %q, want %q", got, []string{"New chart"})
+	}
+}
+
+func TestApplyParagraphsRequestsDeeplyNestedList(t *testing.T) {
+	d := &Deck{styles: map[string]*slides.TextStyle{}}
+	p := func(value string, bullet Bullet, nesting int) *Paragraph {
+		return &Paragraph{Fragments: []*Fragment{{Value: value}}, Bullet: bullet, Nesting: nesting}
+	}
+	paragraphs := []*Paragraph{
+		p("1", BulletNumbered, 0),
+		p("a", BulletDash, 1),
+		p("i", BulletNumbered, 2),
+		p("x", BulletDash, 3),
+		p("y", BulletNumbered, 4),
+		p("2", BulletNumbered, 0),
+		p("text", BulletNone, 0),
+		p("b", BulletDash, 0),
+		p("c", BulletNumbered, 0),
+	}
+	reqs, styleReqs, err := d.applyParagraphsRequests("body", paragraphs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n\ta\n\t\ti\n\t\t\tx\n\t\t\t\ty\n2\ntext\nb\nc"; reqs[0].InsertText.Text != want {
+		t.Errorf("got text %q, want %q", reqs[0].InsertText.Text, want)
+	}
+
+	type bulletRange struct {
+		preset     string
+		start, end int64
+	}
+	var got []bulletRange
+	for _, r := range styleReqs {
+		if b := r.CreateParagraphBullets; b != nil {
+			got = append(got, bulletRange{b.BulletPreset, *b.TextRange.StartIndex, *b.TextRange.EndIndex})
+		}
+	}
+	// The nested lists belong to the top level list, so that its numbering continues after them.
+	want := []bulletRange{
+		{"NUMBERED_DIGIT_ALPHA_ROMAN", 29, 30},
+		{"BULLET_DISC_CIRCLE_SQUARE", 27, 29},
+		{"NUMBERED_DIGIT_ALPHA_ROMAN", 0, 22},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(bulletRange{})); diff != "" {
+		t.Errorf("bullet ranges mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyPreservesEditedSlides(t *testing.T) {
+	ss := Slides{
+		{Layout: "Title and Content", Titles: []string{"first"}},
+		{Layout: "Title and Content", Titles: []string{"second"}},
+	}
+	for _, preserve := range []bool{false, true} {
+		p := newFakePresentation("a", "b")
+		p.Slides[1].SlideProperties.NotesPage.PageElements[0].Shape.Text = &slides.TextContent{
+			TextElements: []*slides.TextElement{{
+				TextRun: &slides.TextRun{Content: "tweaked by hand " + editedMarker + "\n"},
+			}},
+		}
+		s := &fakeServer{presentation: p}
+		result, err := newFakeDeck(t, s, WithPreserveEditedSlides(preserve)).Apply(t.Context(), ss)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 2
+		if preserve {
+			want = 1
+		}
+		if result.Updated != want {
+			t.Errorf("preserve %t: got %d updated, want %d", preserve, result.Updated, want)
+		}
+	}
+}
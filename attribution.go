@@ -0,0 +1,37 @@
+package deck
+
+import (
+	"context"
+	"net/http"
+)
+
+type requestAttributionKey struct{}
+
+// WithRequestAttribution returns a copy of ctx that attributes API requests made with it to the end user id.
+// The id is sent as the quotaUser parameter of Google Slides and Drive API requests,
+// so that quota is applied per end user.
+func WithRequestAttribution(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestAttributionKey{}, id)
+}
+
+func requestAttribution(ctx context.Context) string {
+	id, _ := ctx.Value(requestAttributionKey{}).(string)
+	return id
+}
+
+// attributionTransport sets the quotaUser parameter from the request attribution of the request context.
+type attributionTransport struct {
+	base http.RoundTripper
+}
+
+func (t *attributionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := requestAttribution(req.Context())
+	if id == "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	q := req.URL.Query()
+	q.Set("quotaUser", id)
+	req.URL.RawQuery = q.Encode()
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,38 @@
+package deck
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestRequestAttribution(t *testing.T) {
+	var got []string
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, r.URL.Query().Get("quotaUser"))
+			_ = json.NewEncoder(w).Encode(&drive.File{Name: "title"})
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	if _, err := d.Title(WithRequestAttribution(t.Context(), "user-1")); err != nil {
+		t.Fatal(err)
+	}
+	d.title = ""
+	if _, err := d.Title(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want 2", len(got))
+	}
+	if got[0] != "user-1" {
+		t.Errorf("got quotaUser %q, want %q", got[0], "user-1")
+	}
+	if got[1] != "" {
+		t.Errorf("got quotaUser %q without attribution, want none", got[1])
+	}
+}
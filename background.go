@@ -0,0 +1,102 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// Background represents the background of a page.
+// Set either Color (#RRGGBB) or Image (a path or URL of an image).
+// The zero value clears the background so that it is inherited from the layout.
+type Background struct {
+	Color string
+	Image string
+}
+
+// SetPageBackground sets the background of the page at index.
+// Image backgrounds are uploaded through the same storage as content images.
+func (d *Deck) SetPageBackground(ctx context.Context, index int, bg Background) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if bg.Color != "" && bg.Image != "" {
+		return fmt.Errorf("background color and image cannot be set at the same time")
+	}
+	if index < 0 || len(d.presentation.Slides) <= index {
+		return fmt.Errorf("page index out of range (0-%d): %d", len(d.presentation.Slides)-1, index)
+	}
+	// Fields in the mask that are left unset are reset, so an empty fill inherits the background from the layout.
+	fill := &slides.PageBackgroundFill{}
+	fields := "pageBackgroundFill"
+	switch {
+	case bg.Color != "":
+		rgb, err := parseHexColor(bg.Color)
+		if err != nil {
+			return err
+		}
+		fill.SolidFill = &slides.SolidFill{
+			Color: &slides.OpaqueColor{RgbColor: rgb},
+		}
+		fields = "pageBackgroundFill.solidFill.color"
+	case bg.Image != "":
+		image, err := d.fetchImage(bg.Image, false)
+		if err != nil {
+			return fmt.Errorf("failed to read background image: %w", err)
+		}
+		if d.dryRun {
+			d.logger.Info("skip setting page background because of dry-run", slog.Int("index", index))
+			return nil
+		}
+		data, mimeType, err := d.imageUploadData(image)
+		if err != nil {
+			return err
+		}
+		storage := d.getStorage()
+		publicURL, uploadedID, err := storage.Upload(ctx, data, string(mimeType))
+		if err != nil {
+			d.stats().addError(ErrorTypeUpload)
+			return fmt.Errorf("failed to upload background image: %w", err)
+		}
+		d.stats().imagesUploaded.Add(1)
+		if !d.keepsUploadedImages() {
+			// The same uploaded ID may be shared with a concurrent Apply, so it is deleted only by the last user.
+			uploadRefs.acquire(uploadedID)
+			defer func() {
+				if !uploadRefs.release(uploadedID) {
+					d.logger.Debug("uploaded image is still in use by another run", slog.String("id", uploadedID))
+					return
+				}
+				if err := storage.Delete(ctx, uploadedID); err != nil {
+					d.logger.Error("failed to delete uploaded image",
+						slog.String("id", uploadedID),
+						slog.Any("error", err))
+				}
+			}()
+		}
+		fill.StretchedPictureFill = &slides.StretchedPictureFill{ContentUrl: publicURL}
+		fields = "pageBackgroundFill.stretchedPictureFill.contentUrl"
+	}
+	req := &slides.Request{
+		UpdatePageProperties: &slides.UpdatePagePropertiesRequest{
+			ObjectId: d.presentation.Slides[index].ObjectId,
+			PageProperties: &slides.PageProperties{
+				PageBackgroundFill: fill,
+			},
+			Fields: fields,
+		},
+	}
+	if err := d.batchUpdate(ctx, []*slides.Request{req}); err != nil {
+		return fmt.Errorf("failed to set page background: %w", err)
+	}
+	if err := d.refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh presentation after setting page background: %w", err)
+	}
+	d.logger.Info("set page background", slog.Int("index", index))
+	return nil
+}
@@ -0,0 +1,111 @@
+package deck
+
+import (
+	"testing"
+)
+
+func TestSetPageBackground(t *testing.T) {
+	tests := []struct {
+		name       string
+		bg         Background
+		wantFields string
+		wantURL    string
+	}{
+		{"color", Background{Color: "#ff0000"}, "pageBackgroundFill.solidFill.color", ""},
+		{"image", Background{Image: "testdata/test.png"}, "pageBackgroundFill.stretchedPictureFill.contentUrl", "https://example.com/uploaded-1"},
+		{"inherit", Background{}, "pageBackgroundFill", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeServer{presentation: newFakePresentation("first", "second")}
+			storage := &countingStorage{}
+			d := newFakeDeck(t, s, WithStorage(storage))
+			if err := d.refresh(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := d.SetPageBackground(t.Context(), 1, tt.bg); err != nil {
+				t.Fatal(err)
+			}
+			if len(s.batchUpdates) != 1 {
+				t.Fatalf("got %d batch updates, want 1", len(s.batchUpdates))
+			}
+			req := s.batchUpdates[0].Requests[0].UpdatePageProperties
+			if req.ObjectId != "slide-1" {
+				t.Errorf("got object id %q, want %q", req.ObjectId, "slide-1")
+			}
+			if req.Fields != tt.wantFields {
+				t.Errorf("got fields %q, want %q", req.Fields, tt.wantFields)
+			}
+			fill := req.PageProperties.PageBackgroundFill
+			if tt.bg.Color != "" && (fill.SolidFill == nil || fill.SolidFill.Color.RgbColor.Red != 1) {
+				t.Errorf("got solid fill %+v, want red", fill.SolidFill)
+			}
+			var gotURL string
+			if fill.StretchedPictureFill != nil {
+				gotURL = fill.StretchedPictureFill.ContentUrl
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("got content url %q, want %q", gotURL, tt.wantURL)
+			}
+			if tt.wantURL != "" && storage.deletes["uploaded-1"] != 1 {
+				t.Errorf("uploaded background image was not deleted: %v", storage.deletes)
+			}
+		})
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("first")}
+		storage := &countingStorage{}
+		d := newFakeDeck(t, s, WithStorage(storage), WithDryRun(true))
+		if err := d.refresh(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.SetPageBackground(t.Context(), 0, Background{Image: "testdata/test.png"}); err != nil {
+			t.Fatal(err)
+		}
+		if storage.uploads != 0 {
+			t.Errorf("got %d uploads, want 0", storage.uploads)
+		}
+		if len(s.batchUpdates) != 0 {
+			t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+		}
+	})
+
+	t.Run("shared upload", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("first")}
+		storage := &countingStorage{}
+		d := newFakeDeck(t, s, WithStorage(storage))
+		if err := d.refresh(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		// Another run still references the uploaded image.
+		uploadRefs.acquire("uploaded-1")
+		if err := d.SetPageBackground(t.Context(), 0, Background{Image: "testdata/test.png"}); err != nil {
+			t.Fatal(err)
+		}
+		if storage.deletes["uploaded-1"] != 0 {
+			t.Errorf("uploaded image still in use was deleted: %v", storage.deletes)
+		}
+		if !uploadRefs.release("uploaded-1") {
+			t.Error("background upload was not released")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("first")}
+		d := newFakeDeck(t, s)
+		if err := d.refresh(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.SetPageBackground(t.Context(), 1, Background{Color: "#ff0000"}); err == nil {
+			t.Error("expected error for out-of-range index but got none")
+		}
+		if err := d.SetPageBackground(t.Context(), 0, Background{Color: "red"}); err == nil {
+			t.Error("expected error for invalid color but got none")
+		}
+		if len(s.batchUpdates) != 0 {
+			t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package deck
+
+import (
+	"slices"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// Although there is no explicit request limit specified in the Google Slides API specifications,
+// we will set an upper limit as a precaution.
+// After testing several times, it handles around 1,000 requests without any issues,
+// so we use half of it by default to leave a margin for large requests.
+const defaultBatchSize = 500
+
+// batchSizeOrDefault returns the maximum number of requests in a single batch update.
+func (d *Deck) batchSizeOrDefault() int {
+	if d.batchSize < 1 {
+		return defaultBatchSize
+	}
+	return d.batchSize
+}
+
+// chunkRequests splits the requests into chunks of at most size requests, preserving the order.
+// A request that creates an object and the following requests that refer to the object are kept in the same chunk,
+// so that an object is never left half-populated by a failure between chunks.
+// Such a unit of requests larger than size makes a chunk by itself.
+func chunkRequests(requests []*slides.Request, size int) [][]*slides.Request {
+	if len(requests) <= size {
+		return [][]*slides.Request{requests}
+	}
+
+	// Group the requests into units that must not be split.
+	// Units are consecutive, so they are kept as the index of the first request of each unit.
+	var starts []int
+	createdAt := map[string]int{} // key: objectID, value: index of the request that creates the object
+	for i, r := range requests {
+		merge := len(starts)
+		for _, id := range referredObjectIDs(r) {
+			at, ok := createdAt[id]
+			if !ok {
+				continue
+			}
+			u, found := slices.BinarySearch(starts, at)
+			if !found {
+				u--
+			}
+			merge = min(merge, u)
+		}
+		if merge < len(starts) {
+			// Merge the units from the one that created the referred object into one.
+			starts = starts[:merge+1]
+		} else {
+			starts = append(starts, i)
+		}
+		if id := createdObjectID(r); id != "" {
+			createdAt[id] = i
+		}
+	}
+	units := make([][]*slides.Request, len(starts))
+	for u, start := range starts {
+		end := len(requests)
+		if u+1 < len(starts) {
+			end = starts[u+1]
+		}
+		units[u] = requests[start:end]
+	}
+
+	var chunks [][]*slides.Request
+	var chunk []*slides.Request
+	for _, u := range units {
+		if len(chunk) > 0 && len(chunk)+len(u) > size {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+		chunk = append(chunk, u...)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// createdObjectID returns the object ID of the object created by the request, or empty if there is none.
+func createdObjectID(r *slides.Request) string {
+	switch {
+	case r.CreateSlide != nil:
+		return r.CreateSlide.ObjectId
+	case r.CreateShape != nil:
+		return r.CreateShape.ObjectId
+	case r.CreateImage != nil:
+		return r.CreateImage.ObjectId
+	case r.CreateTable != nil:
+		return r.CreateTable.ObjectId
+	case r.CreateLine != nil:
+		return r.CreateLine.ObjectId
+	case r.CreateVideo != nil:
+		return r.CreateVideo.ObjectId
+	case r.CreateSheetsChart != nil:
+		return r.CreateSheetsChart.ObjectId
+	}
+	return ""
+}
+
+// referredObjectIDs returns the object IDs of the existing objects that the request refers to.
+func referredObjectIDs(r *slides.Request) []string {
+	var ids []string
+	pageOf := func(p *slides.PageElementProperties) {
+		if p != nil {
+			ids = append(ids, p.PageObjectId)
+		}
+	}
+	switch {
+	case r.CreateShape != nil:
+		pageOf(r.CreateShape.ElementProperties)
+	case r.CreateImage != nil:
+		pageOf(r.CreateImage.ElementProperties)
+	case r.CreateTable != nil:
+		pageOf(r.CreateTable.ElementProperties)
+	case r.CreateLine != nil:
+		pageOf(r.CreateLine.ElementProperties)
+	case r.CreateVideo != nil:
+		pageOf(r.CreateVideo.ElementProperties)
+	case r.CreateSheetsChart != nil:
+		pageOf(r.CreateSheetsChart.ElementProperties)
+	case r.InsertText != nil:
+		ids = append(ids, r.InsertText.ObjectId)
+	case r.DeleteText != nil:
+		ids = append(ids, r.DeleteText.ObjectId)
+	case r.UpdateTextStyle != nil:
+		ids = append(ids, r.UpdateTextStyle.ObjectId)
+	case r.UpdateParagraphStyle != nil:
+		ids = append(ids, r.UpdateParagraphStyle.ObjectId)
+	case r.CreateParagraphBullets != nil:
+		ids = append(ids, r.CreateParagraphBullets.ObjectId)
+	case r.DeleteParagraphBullets != nil:
+		ids = append(ids, r.DeleteParagraphBullets.ObjectId)
+	case r.DeleteObject != nil:
+		ids = append(ids, r.DeleteObject.ObjectId)
+	case r.UpdateShapeProperties != nil:
+		ids = append(ids, r.UpdateShapeProperties.ObjectId)
+	case r.UpdateImageProperties != nil:
+		ids = append(ids, r.UpdateImageProperties.ObjectId)
+	case r.UpdateLineProperties != nil:
+		ids = append(ids, r.UpdateLineProperties.ObjectId)
+	case r.UpdatePageElementTransform != nil:
+		ids = append(ids, r.UpdatePageElementTransform.ObjectId)
+	case r.UpdatePageElementAltText != nil:
+		ids = append(ids, r.UpdatePageElementAltText.ObjectId)
+	case r.UpdatePageProperties != nil:
+		ids = append(ids, r.UpdatePageProperties.ObjectId)
+	case r.UpdateSlideProperties != nil:
+		ids = append(ids, r.UpdateSlideProperties.ObjectId)
+	case r.UpdateSlidesPosition != nil:
+		ids = append(ids, r.UpdateSlidesPosition.SlideObjectIds...)
+	case r.ReplaceImage != nil:
+		ids = append(ids, r.ReplaceImage.ImageObjectId)
+	case r.ReplaceAllText != nil:
+		ids = append(ids, r.ReplaceAllText.PageObjectIds...)
+	case r.UpdateTableCellProperties != nil:
+		ids = append(ids, r.UpdateTableCellProperties.ObjectId)
+	case r.UpdateTableBorderProperties != nil:
+		ids = append(ids, r.UpdateTableBorderProperties.ObjectId)
+	case r.UpdateTableColumnProperties != nil:
+		ids = append(ids, r.UpdateTableColumnProperties.ObjectId)
+	case r.UpdateTableRowProperties != nil:
+		ids = append(ids, r.UpdateTableRowProperties.ObjectId)
+	case r.MergeTableCells != nil:
+		ids = append(ids, r.MergeTableCells.ObjectId)
+	case r.UnmergeTableCells != nil:
+		ids = append(ids, r.UnmergeTableCells.ObjectId)
+	case r.InsertTableRows != nil:
+		ids = append(ids, r.InsertTableRows.TableObjectId)
+	case r.InsertTableColumns != nil:
+		ids = append(ids, r.InsertTableColumns.TableObjectId)
+	case r.DeleteTableRow != nil:
+		ids = append(ids, r.DeleteTableRow.TableObjectId)
+	case r.DeleteTableColumn != nil:
+		ids = append(ids, r.DeleteTableColumn.TableObjectId)
+	case r.DuplicateObject != nil:
+		ids = append(ids, r.DuplicateObject.ObjectId)
+	}
+	return ids
+}
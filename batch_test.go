@@ -0,0 +1,96 @@
+package deck
+
+import (
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestChunkRequests(t *testing.T) {
+	createShape := func(id string) *slides.Request {
+		return &slides.Request{CreateShape: &slides.CreateShapeRequest{ObjectId: id, ShapeType: "TEXT_BOX"}}
+	}
+	insertText := func(id, text string) *slides.Request {
+		return &slides.Request{InsertText: &slides.InsertTextRequest{ObjectId: id, Text: text}}
+	}
+	deleteObject := func(id string) *slides.Request {
+		return &slides.Request{DeleteObject: &slides.DeleteObjectRequest{ObjectId: id}}
+	}
+
+	tests := []struct {
+		name     string
+		requests []*slides.Request
+		size     int
+		want     []int
+	}{
+		{
+			name:     "fits in one chunk",
+			requests: []*slides.Request{deleteObject("a"), deleteObject("b")},
+			size:     2,
+			want:     []int{2},
+		},
+		{
+			name:     "independent requests",
+			requests: []*slides.Request{deleteObject("a"), deleteObject("b"), deleteObject("c")},
+			size:     2,
+			want:     []int{2, 1},
+		},
+		{
+			name: "create and populate stay together",
+			requests: []*slides.Request{
+				deleteObject("x"),
+				createShape("a"),
+				insertText("a", "hello"),
+				deleteObject("y"),
+			},
+			size: 2,
+			want: []int{1, 2, 1},
+		},
+		{
+			name: "text equal to an object ID is not a reference",
+			requests: []*slides.Request{
+				createShape("a"),
+				deleteObject("x"),
+				insertText("b", "a"),
+			},
+			size: 2,
+			want: []int{2, 1},
+		},
+		{
+			name: "dependent unit larger than size",
+			requests: []*slides.Request{
+				createShape("a"),
+				insertText("a", "hello"),
+				deleteObject("y"),
+				insertText("a", "world"),
+				deleteObject("z"),
+			},
+			size: 2,
+			want: []int{4, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := chunkRequests(tt.requests, tt.size)
+			var got []int
+			var flattened []*slides.Request
+			for _, c := range chunks {
+				got = append(got, len(c))
+				flattened = append(flattened, c...)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got chunk sizes %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got chunk sizes %v, want %v", got, tt.want)
+				}
+			}
+			for i, r := range flattened {
+				if r != tt.requests[i] {
+					t.Fatalf("request %d is out of order", i)
+				}
+			}
+		})
+	}
+}
@@ -47,6 +47,14 @@ var userAgent = "k1LoW-deck/" + version.Version + " (+https://github.com/k1LoW/d
 // getHTTPClient returns the appropriate client option based on available credentials.
 func (d *Deck) getHTTPClient(ctx context.Context) (*http.Client, error) {
 	client, err := func(ctx context.Context) (*http.Client, error) {
+		if d.tokenSource != nil {
+			d.logger.Debug("using token source from option")
+			return oauth2.NewClient(ctx, d.tokenSource), nil
+		}
+		if len(d.serviceAccountJSON) > 0 {
+			d.logger.Debug("using service account key authentication from option")
+			return d.getServiceAccountHTTPClient(ctx, string(d.serviceAccountJSON))
+		}
 		if credsJSON := os.Getenv(EnvServiceAccountKey); credsJSON != "" {
 			d.logger.Debug("using service account key authentication")
 			return d.getServiceAccountHTTPClient(ctx, credsJSON)
@@ -110,11 +118,8 @@ func (d *Deck) getDefaultHTTPClient(ctx context.Context) (_ *http.Client, err er
 		return nil, err
 	}
 
-	tokenPath := filepath.Join(config.StateHomePath(), "token.json")
-	if d.profile != "" {
-		tokenPath = filepath.Join(config.StateHomePath(), fmt.Sprintf("token-%s.json", d.profile))
-	}
-	token, err := d.tokenFromFile(tokenPath)
+	tokenPath := filepath.Join(stateDir(d.profile), "token.json")
+	token, err := d.loadToken(tokenPath)
 	if err != nil {
 		token, err = d.getTokenFromWeb(ctx, cfg)
 		if err != nil {
@@ -249,6 +254,28 @@ func (d *Deck) getTokenFromWeb(ctx context.Context, config *oauth2.Config) (_ *o
 	return token, nil
 }
 
+// loadToken loads the token of the profile from tokenPath.
+// A token of a named profile that is stored in the state home as token-{profile}.json by an older version
+// is moved to tokenPath.
+func (d *Deck) loadToken(tokenPath string) (*oauth2.Token, error) {
+	token, err := d.tokenFromFile(tokenPath)
+	if err == nil || d.profile == "" || !errors.Is(err, os.ErrNotExist) {
+		return token, err
+	}
+	legacyPath := filepath.Join(stateHome(), fmt.Sprintf("token-%s.json", d.profile))
+	token, err = d.tokenFromFile(legacyPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.saveToken(tokenPath, token); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(legacyPath); err != nil {
+		d.logger.Warn("failed to remove the old token file", slog.String("path", legacyPath), slog.Any("error", err))
+	}
+	return token, nil
+}
+
 func (d *Deck) tokenFromFile(file string) (_ *oauth2.Token, err error) {
 	defer func() {
 		err = errors.WithStack(err)
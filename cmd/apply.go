@@ -44,6 +44,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	envHTTPUploadBaseURL  = "DECK_HTTP_UPLOAD_BASE_URL"
+	envHTTPUploadPort     = "DECK_HTTP_UPLOAD_PORT"
+	defaultHTTPUploadPort = "8080"
+	envImgurClientID      = "DECK_IMGUR_CLIENT_ID"
+)
+
 var (
 	presentationID      string
 	title               string
@@ -55,6 +62,9 @@ var (
 	applyFolderID       string
 	imageUploadCmd      string
 	imageDeleteCmd      string
+	dryRun              bool
+	forceFullApply      bool
+	preserveEdited      bool
 	tb                  = tail.New(30)
 )
 
@@ -66,6 +76,9 @@ var applyCmd = &cobra.Command{
 		if page != "" && watch {
 			return fmt.Errorf("cannot use --page and --watch together")
 		}
+		if dryRun && watch {
+			return fmt.Errorf("cannot use --dry-run and --watch together")
+		}
 		if len(args) == 2 && presentationID != "" {
 			return fmt.Errorf("cannot use --presentation-id with two arguments")
 		}
@@ -89,15 +102,15 @@ var applyCmd = &cobra.Command{
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		// Use flag applyFolderID if provided, otherwise use config folderID
-		targetFolderID := applyFolderID
-		if targetFolderID == "" && cfg.FolderID != "" {
-			targetFolderID = cfg.FolderID
-		}
 		m, err := md.ParseFile(f, cfg)
 		if err != nil {
 			return err
 		}
+		if m.Frontmatter != nil {
+			for _, k := range m.Frontmatter.UnknownKeys {
+				cmd.Println(color.YellowString("WARNING: unknown frontmatter key %q is ignored.", k))
+			}
+		}
 		if len(args) == 1 {
 			if m.Frontmatter != nil {
 				if presentationID == "" && m.Frontmatter.PresentationID != "" {
@@ -109,6 +122,15 @@ var applyCmd = &cobra.Command{
 			}
 		}
 
+		// Use flag applyFolderID if provided, otherwise use frontmatter or config folderID
+		targetFolderID := applyFolderID
+		if targetFolderID == "" && m.Frontmatter != nil {
+			targetFolderID = m.Frontmatter.FolderID
+		}
+		if targetFolderID == "" && cfg.FolderID != "" {
+			targetFolderID = cfg.FolderID
+		}
+
 		if presentationID == "" {
 			return fmt.Errorf("presentation ID is required, please specify it with --presentation-id or in the frontmatter of the markdown file")
 		}
@@ -156,12 +178,44 @@ var applyCmd = &cobra.Command{
 		if targetFolderID != "" {
 			opts = append(opts, deck.WithFolderID(targetFolderID))
 		}
+		if m.Frontmatter != nil && m.Frontmatter.DefaultLayout != "" {
+			opts = append(opts, deck.WithDefaultLayout(m.Frontmatter.DefaultLayout))
+		}
+		if m.Frontmatter != nil && m.Frontmatter.DefaultTitleLayout != "" {
+			opts = append(opts, deck.WithDefaultTitleLayout(m.Frontmatter.DefaultTitleLayout))
+		}
 		if imageUploadCmd != "" {
 			opts = append(opts, deck.WithImageUploadCmd(imageUploadCmd))
 		}
 		if imageDeleteCmd != "" {
 			opts = append(opts, deck.WithImageDeleteCmd(imageDeleteCmd))
 		}
+		if dryRun {
+			opts = append(opts, deck.WithDryRun(true))
+		}
+		opts = append(opts, deck.WithIncrementalApply(true))
+		if forceFullApply {
+			opts = append(opts, deck.WithForceFullApply(true))
+		}
+		if preserveEdited {
+			opts = append(opts, deck.WithPreserveEditedSlides(true))
+		}
+		if baseURL := os.Getenv(envHTTPUploadBaseURL); baseURL != "" && imageUploadCmd == "" {
+			port := os.Getenv(envHTTPUploadPort)
+			if port == "" {
+				port = defaultHTTPUploadPort
+			}
+			s := deck.NewHTTPServerStorage(baseURL)
+			if err := s.Start(":" + port); err != nil {
+				return err
+			}
+			defer func() {
+				_ = s.Close(context.Background())
+			}()
+			opts = append(opts, deck.WithStorage(s))
+		} else if clientID := os.Getenv(envImgurClientID); clientID != "" && imageUploadCmd == "" {
+			opts = append(opts, deck.WithStorage(deck.NewImgurStorage(clientID)))
+		}
 		d, err := deck.New(ctx, opts...)
 		if err != nil {
 			if errors.Is(err, deck.HTTPClientError) {
@@ -169,6 +223,9 @@ var applyCmd = &cobra.Command{
 			}
 			return err
 		}
+		defer func() {
+			_ = d.Close()
+		}()
 		if title != "" {
 			if err := d.UpdateTitle(ctx, title); err != nil {
 				return err
@@ -179,10 +236,11 @@ var applyCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("failed to convert markdown contents to slides: %w", err)
 			}
-			if err := d.Apply(ctx, slides); err != nil {
+			result, err := d.Apply(ctx, slides)
+			if err != nil {
 				return err
 			}
-			logger.Info("initial apply completed", slog.String("presentation_id", presentationID))
+			logger.Info("initial apply completed", append([]any{slog.String("presentation_id", presentationID)}, resultAttrs(result)...)...)
 
 			return watchFile(cmd.Context(), cfg, f, contents, d)
 		} else {
@@ -194,10 +252,15 @@ var applyCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("failed to convert markdown contents to slides: %w", err)
 			}
-			if err := d.ApplyPages(ctx, slides, pages); err != nil {
+			result, err := d.ApplyPages(ctx, slides, pages)
+			if err != nil {
 				return err
 			}
-			logger.Info("apply completed", slog.String("presentation_id", presentationID), slog.Any("pages", pages))
+			if dryRun {
+				cmd.Print(result.String())
+				return nil
+			}
+			logger.Info("apply completed", append([]any{slog.String("presentation_id", presentationID), slog.Any("pages", pages)}, resultAttrs(result)...)...)
 		}
 		return nil
 	},
@@ -213,6 +276,9 @@ func init() {
 	applyCmd.Flags().StringVarP(&imageUploadCmd, "image-upload-command", "u", "", "command to upload images (e.g., 'my-uploader upload')")
 	applyCmd.Flags().StringVarP(&imageDeleteCmd, "image-delete-command", "d", "", "command to delete uploaded images (e.g., 'my-uploader delete')")
 	applyCmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch for changes")
+	applyCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "show the changes to apply without applying them")
+	applyCmd.Flags().BoolVarP(&forceFullApply, "force", "", false, "apply all pages, including those unchanged since the last apply")
+	applyCmd.Flags().BoolVarP(&preserveEdited, "preserve-edited", "", false, "leave pages marked as edited in their speaker notes untouched")
 	applyCmd.Flags().CountVarP(&verbosity, "verbose", "v", "verbose output (can be used multiple times for more verbosity)")
 }
 
@@ -370,7 +436,8 @@ func watchFile(ctx context.Context, cfg *config.Config, filePath string, oldCont
 				logger.Error("failed to convert markdown contents to slides", slog.String("error", err.Error()))
 				continue
 			}
-			if err := d.ApplyPages(ctx, slides, changedPages); err != nil {
+			result, err := d.ApplyPages(ctx, slides, changedPages)
+			if err != nil {
 				slogArgs := []any{slog.String("error", err.Error())}
 				if verbosity > 1 {
 					slogArgs = append(slogArgs, slog.String("stacktrace", errors.StackTraces(err).String()))
@@ -379,9 +446,21 @@ func watchFile(ctx context.Context, cfg *config.Config, filePath string, oldCont
 				continue
 			}
 
-			logger.Info("applied changes", slog.Any("pages", changedPages))
+			logger.Info("applied changes", append([]any{slog.Any("pages", changedPages)}, resultAttrs(result)...)...)
 
 			oldContents = newContents
 		}
 	}
 }
+
+// resultAttrs returns log attributes summarizing the result of apply.
+func resultAttrs(result *deck.ApplyResult) []any {
+	return []any{
+		slog.Int("created", result.Created),
+		slog.Int("updated", result.Updated),
+		slog.Int("moved", result.Moved),
+		slog.Int("deleted", result.Deleted),
+		slog.Int("images_uploaded", result.ImagesUploaded),
+		slog.Int("images_reused", result.ImagesReused),
+	}
+}
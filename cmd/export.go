@@ -88,15 +88,7 @@ var exportCmd = &cobra.Command{
 				return nil
 			}
 		}
-		f, err := os.Create(out)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		if err := d.Export(ctx, f); err != nil {
-			return err
-		}
-		return nil
+		return d.ExportTo(ctx, out)
 	},
 }
 
@@ -23,6 +23,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 	"github.com/k1LoW/deck"
@@ -43,7 +44,8 @@ var newCmd = &cobra.Command{
 	Short: "create new presentation",
 	Long: `create new presentation.
 
-If a markdown file is specified, frontmatter with title and presentationID will be added to the file.
+If a markdown file is specified, its frontmatter (title, folderID, basePresentationID and defaultTitleLayout) is used,
+and frontmatter with title and presentationID will be added to the file.
 If the file doesn't exist, it will be created.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -54,7 +56,23 @@ If the file doesn't exist, it will be created.`,
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
+		// Use the frontmatter of the markdown file, if it exists, over the config
+		var fm *md.Frontmatter
+		if len(args) > 0 {
+			if _, err := os.Stat(args[0]); err == nil {
+				fm, err = md.ReadFrontmatter(args[0])
+				if err != nil {
+					return err
+				}
+				for _, k := range fm.UnknownKeys {
+					cmd.Println(color.YellowString("WARNING: unknown frontmatter key %q is ignored.", k))
+				}
+			}
+		}
 		basePresentationID := cfg.BasePresentationID
+		if fm != nil && fm.BasePresentationID != "" {
+			basePresentationID = fm.BasePresentationID
+		}
 		if from != "" {
 			cmd.Println(color.YellowString("WARNING: --from is deprecated. Please use --base flag instead."))
 			basePresentationID = from
@@ -62,9 +80,15 @@ If the file doesn't exist, it will be created.`,
 		if base != "" {
 			basePresentationID = base
 		}
+		if folderID == "" && fm != nil {
+			folderID = fm.FolderID
+		}
 		if folderID == "" {
 			folderID = cfg.FolderID
 		}
+		if title == "" && fm != nil {
+			title = fm.Title
+		}
 
 		opts := []deck.Option{
 			deck.WithProfile(profile),
@@ -72,6 +96,9 @@ If the file doesn't exist, it will be created.`,
 		if folderID != "" {
 			opts = append(opts, deck.WithFolderID(folderID))
 		}
+		if fm != nil && fm.DefaultTitleLayout != "" {
+			opts = append(opts, deck.WithDefaultTitleLayout(fm.DefaultTitleLayout))
+		}
 		d, err := func() (*deck.Deck, error) {
 			if basePresentationID != "" {
 				return deck.CreateFrom(ctx, basePresentationID, opts...)
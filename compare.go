@@ -25,7 +25,8 @@ func (s *Slide) Equal(other *Slide) bool {
 		imagesEquivalent(s.Images, other.Images) &&
 		blockQuotesEqual(s.BlockQuotes, other.BlockQuotes) &&
 		tablesEqual(s.Tables, other.Tables) &&
-		s.SpeakerNote == other.SpeakerNote
+		s.SpeakerNote == other.SpeakerNote &&
+		s.Accent == other.Accent
 }
 
 func bodiesEqual(bodies1, bodies2 []*Body) bool {
@@ -51,7 +52,7 @@ func imagesEquivalent(images1, images2 []*Image) bool {
 	slices.SortFunc(sorted2, f)
 
 	return slices.EqualFunc(sorted1, sorted2, func(a, b *Image) bool {
-		return a.Equivalent(b)
+		return a.Equivalent(b) && a.alt == b.alt && a.width == b.width && a.height == b.height
 	})
 }
 
@@ -123,7 +124,16 @@ func tablesEqual(tables1, tables2 []*Table) bool {
 		if a == nil || b == nil {
 			return a == b
 		}
-		return slices.EqualFunc(a.Rows, b.Rows, tableRowEqual)
+		if (a.Style == nil) != (b.Style == nil) || (a.Style != nil && *a.Style != *b.Style) {
+			return false
+		}
+		return slices.EqualFunc(a.Rows, b.Rows, tableRowEqual) &&
+			slices.EqualFunc(a.Merges, b.Merges, func(m1, m2 *TableMerge) bool {
+				if m1 == nil || m2 == nil {
+					return m1 == m2
+				}
+				return *m1 == *m2
+			})
 	})
 }
 
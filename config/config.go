@@ -12,6 +12,10 @@ import (
 type Config struct {
 	// Whether to display line breaks in the document as line breaks
 	Breaks *bool `yaml:"breaks,omitempty" json:"breaks,omitempty"`
+	// Whether to preserve consecutive blank lines within a slide as empty paragraphs
+	PreserveBlankLines *bool `yaml:"preserveBlankLines,omitempty" json:"preserveBlankLines,omitempty"`
+	// Whether to merge table cells consisting of only "<" or "^" into the cell on the left or above
+	MergeTableCells *bool `yaml:"mergeTableCells,omitempty" json:"mergeTableCells,omitempty"`
 	// Conditions for default
 	Defaults []DefaultCondition `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 	// command to convert code blocks to images
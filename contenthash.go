@@ -0,0 +1,133 @@
+package deck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// ContentHash returns a stable hash over the semantic content of the slides:
+// layouts, text, image sources and their order. Incidental whitespace in text is ignored.
+// Callers can store it to skip applying slides that have not changed.
+func (ss Slides) ContentHash() string {
+	h := sha256.New()
+	for i, s := range ss {
+		writeHashField(h, "slide", fmt.Sprintf("%d", i))
+		if s == nil {
+			continue
+		}
+		writeHashField(h, "layout", s.Layout)
+		writeHashField(h, "freeze", fmt.Sprintf("%t", s.Freeze))
+		writeHashField(h, "skip", fmt.Sprintf("%t", s.Skip))
+		for _, t := range s.Titles {
+			writeHashField(h, "title", normalizeSpace(t))
+		}
+		for _, b := range s.TitleBodies {
+			writeHashParagraphs(h, "title_body", b.Paragraphs)
+		}
+		for _, t := range s.Subtitles {
+			writeHashField(h, "subtitle", normalizeSpace(t))
+		}
+		for _, b := range s.SubtitleBodies {
+			writeHashParagraphs(h, "subtitle_body", b.Paragraphs)
+		}
+		for _, b := range s.Bodies {
+			writeHashParagraphs(h, "body", b.Paragraphs)
+		}
+		for _, img := range s.Images {
+			writeHashField(h, "image", imageSource(img))
+			if img != nil {
+				writeHashField(h, "image_link", img.link)
+				writeHashField(h, "image_alt", img.alt)
+				if img.width != "" || img.height != "" {
+					writeHashField(h, "image_size", img.width+"x"+img.height)
+				}
+			}
+		}
+		for _, bq := range s.BlockQuotes {
+			writeHashField(h, "block_quote", fmt.Sprintf("%d", bq.Nesting))
+			writeHashParagraphs(h, "block_quote", bq.Paragraphs)
+		}
+		for _, t := range s.Tables {
+			writeHashField(h, "table", "")
+			if o := t.Style; o != nil {
+				writeHashField(h, "table_style", fmt.Sprintf("%s:%s:%v", o.HeaderBackground, o.TextColor, o.BorderWidth))
+			}
+			for _, m := range t.Merges {
+				writeHashField(h, "table_merge", fmt.Sprintf("%d:%d:%d:%d", m.Row, m.Column, m.RowSpan, m.ColumnSpan))
+			}
+			for _, r := range t.Rows {
+				writeHashField(h, "row", "")
+				for _, c := range r.Cells {
+					writeHashField(h, "cell", fmt.Sprintf("%s:%t", c.Alignment, c.IsHeader))
+					writeHashFragments(h, c.Fragments)
+				}
+			}
+		}
+		writeHashField(h, "speaker_note", normalizeSpace(s.SpeakerNote))
+		writeHashField(h, "accent", s.Accent)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeHashField writes a length-prefixed key and value so that adjacent fields cannot collide.
+func writeHashField(h hash.Hash, key, value string) {
+	_, _ = fmt.Fprintf(h, "%d:%s%d:%s", len(key), key, len(value), value)
+}
+
+func writeHashParagraphs(h hash.Hash, key string, paragraphs []*Paragraph) {
+	for _, p := range paragraphs {
+		if p == nil {
+			continue
+		}
+		writeHashField(h, key, fmt.Sprintf("%s:%d", p.Bullet, p.Nesting))
+		writeHashFragments(h, p.Fragments)
+	}
+}
+
+func writeHashFragments(h hash.Hash, fragments []*Fragment) {
+	// Merge adjacent fragments with the same style so that splitting a run does not change the hash.
+	var (
+		prev *Fragment
+		text strings.Builder
+	)
+	flush := func() {
+		if prev == nil {
+			return
+		}
+		if v := normalizeSpace(text.String()); v != "" {
+			writeHashField(h, "fragment", fmt.Sprintf("%t:%t:%t:%s:%s", prev.Bold, prev.Italic, prev.Code, prev.Link, prev.StyleName))
+			writeHashField(h, "text", v)
+		}
+		text.Reset()
+	}
+	for _, f := range fragments {
+		if f == nil {
+			continue
+		}
+		if !f.StylesEqual(prev) {
+			flush()
+			prev = f
+		}
+		text.WriteString(f.Value)
+	}
+	flush()
+}
+
+// imageSource returns the source of the image: its URL if it was fetched from one, otherwise a hash of its data.
+func imageSource(i *Image) string {
+	if i == nil {
+		return ""
+	}
+	if i.url != "" {
+		return i.url
+	}
+	return hashHex(i.b)
+}
+
+// normalizeSpace collapses runs of whitespace into a single space and trims both ends.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,73 @@
+package deck
+
+import "testing"
+
+func TestContentHash(t *testing.T) {
+	base := func() Slides {
+		return Slides{
+			{
+				Layout: "title",
+				Titles: []string{"Hello world"},
+			},
+			{
+				Layout: "title-and-body",
+				Titles: []string{"Agenda"},
+				Bodies: []*Body{{Paragraphs: []*Paragraph{
+					{Fragments: []*Fragment{{Value: "first item"}}, Bullet: BulletDash},
+					{Fragments: []*Fragment{{Value: "second "}, {Value: "item", Bold: true}}, Bullet: BulletDash},
+				}}},
+				SpeakerNote: "Talk about the agenda.",
+			},
+		}
+	}
+	want := base().ContentHash()
+	if got := base().ContentHash(); got != want {
+		t.Fatalf("hash is not stable: got %s, want %s", got, want)
+	}
+
+	t.Run("whitespace-only changes", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			modify func(ss Slides)
+		}{
+			{"title", func(ss Slides) { ss[0].Titles[0] = "  Hello \n world " }},
+			{"body", func(ss Slides) { ss[1].Bodies[0].Paragraphs[0].Fragments[0].Value = "first\titem " }},
+			{"split fragment", func(ss Slides) {
+				ss[1].Bodies[0].Paragraphs[0].Fragments = []*Fragment{{Value: "first "}, {Value: "item"}}
+			}},
+			{"speaker note", func(ss Slides) { ss[1].SpeakerNote = "Talk about\nthe agenda.\n" }},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ss := base()
+				tt.modify(ss)
+				if got := ss.ContentHash(); got != want {
+					t.Errorf("got %s, want %s", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("content changes", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			modify func(ss Slides) Slides
+		}{
+			{"layout", func(ss Slides) Slides { ss[0].Layout = "section"; return ss }},
+			{"title", func(ss Slides) Slides { ss[0].Titles[0] = "Hello, world"; return ss }},
+			{"style", func(ss Slides) Slides { ss[1].Bodies[0].Paragraphs[1].Fragments[1].Bold = false; return ss }},
+			{"bullet", func(ss Slides) Slides { ss[1].Bodies[0].Paragraphs[0].Bullet = BulletNumbered; return ss }},
+			{"speaker note", func(ss Slides) Slides { ss[1].SpeakerNote = ""; return ss }},
+			{"order", func(ss Slides) Slides { return Slides{ss[1], ss[0]} }},
+			{"image", func(ss Slides) Slides { ss[0].Images = []*Image{{url: "https://example.com/a.png"}}; return ss }},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ss := tt.modify(base())
+				if got := ss.ContentHash(); got == want {
+					t.Errorf("hash did not change: %s", got)
+				}
+			})
+		}
+	})
+}
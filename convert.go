@@ -2,6 +2,7 @@
 package deck
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -66,9 +67,9 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 					continue // Skip if image cannot be created
 				}
 			}
-			if element.Image.ImageProperties != nil && element.Image.ImageProperties.Link != nil {
-				image.link = element.Image.ImageProperties.Link.Url
-			}
+			image.link = imageLink(element.Image)
+			image.alt = markdownImageAlt(element)
+			image.element = element
 			images = append(images, image)
 		case element.Shape != nil && element.Shape.ShapeType == "TEXT_BOX" && element.Shape.Text != nil:
 			if element.Description != descriptionTextboxFromMarkdown {
@@ -82,6 +83,7 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 			// Convert Google Slides table to deck Table
 			table := convertSlidesToTable(element.Table)
 			if table != nil {
+				table.Style = readTableStyleOverride(element)
 				tables = append(tables, table)
 			}
 		}
@@ -94,21 +96,46 @@ func convertToSlide(p *slides.Page, layoutObjectIdMap map[string]*slides.Page) *
 	slide.BlockQuotes = blockQuotes
 	slide.Tables = tables
 
-	// Extract speaker notes
-	if p.SlideProperties != nil && p.SlideProperties.NotesPage != nil {
-		for _, element := range p.SlideProperties.NotesPage.PageElements {
-			if element.Shape != nil && element.Shape.Text != nil && element.Shape.Placeholder != nil {
-				if element.Shape.Placeholder.Type == "BODY" {
-					slide.SpeakerNote = extractText(element.Shape.Text)
-					break
-				}
-			}
-		}
-	}
+	slide.SpeakerNote = extractSpeakerNote(p)
+	slide.Accent = readAccent(p)
 
 	return slide
 }
 
+// extractSpeakerNote extracts the text of the speaker notes of the page.
+func extractSpeakerNote(p *slides.Page) string {
+	element := speakerNotesElement(p)
+	if element == nil {
+		return ""
+	}
+	return extractText(element.Shape.Text)
+}
+
+// speakerNotesElement returns the shape holding the speaker notes of the page.
+// The shape is identified by the speaker notes object ID of the notes page, falling back to the body placeholder.
+func speakerNotesElement(p *slides.Page) *slides.PageElement {
+	if p.SlideProperties == nil || p.SlideProperties.NotesPage == nil {
+		return nil
+	}
+	notesPage := p.SlideProperties.NotesPage
+	var id string
+	if notesPage.NotesProperties != nil {
+		id = notesPage.NotesProperties.SpeakerNotesObjectId
+	}
+	for _, element := range notesPage.PageElements {
+		if element.Shape == nil {
+			continue
+		}
+		if id != "" && element.ObjectId == id {
+			return element
+		}
+		if id == "" && element.Shape.Placeholder != nil && element.Shape.Placeholder.Type == "BODY" {
+			return element
+		}
+	}
+	return nil
+}
+
 // extractText extracts plain text from Shape.Text.
 func extractText(text *slides.TextContent) string {
 	if text == nil || len(text.TextElements) == 0 {
@@ -268,6 +295,7 @@ func convertSlidesToTable(slidesTable *slides.Table) *Table {
 
 		table.Rows[i] = row
 	}
+	table.Merges = readTableMerges(slidesTable)
 
 	return table
 }
@@ -304,3 +332,94 @@ func extractAlignmentFromTableCell(cell *slides.TableCell) string {
 	}
 	return ""
 }
+
+// Markdown renders the slide converted from the presentation as markdown.
+// Text links are reconstructed as [text](url) and linked images as [![alt](url)](link).
+func (s *Slide) Markdown() string {
+	var blocks []string
+	for _, title := range s.Titles {
+		blocks = append(blocks, "# "+title)
+	}
+	for _, subtitle := range s.Subtitles {
+		blocks = append(blocks, "## "+subtitle)
+	}
+	for _, body := range s.Bodies {
+		lines := make([]string, 0, len(body.Paragraphs))
+		for _, p := range body.Paragraphs {
+			lines = append(lines, markdownParagraph(p))
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	for _, image := range s.Images {
+		blocks = append(blocks, markdownImage(image))
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// Markdown renders the slides as markdown, separated by horizontal rules.
+func (s Slides) Markdown() string { //nostyle:recvtype
+	pages := make([]string, 0, len(s))
+	for _, slide := range s {
+		pages = append(pages, slide.Markdown())
+	}
+	return strings.Join(pages, "\n---\n\n")
+}
+
+func markdownParagraph(p *Paragraph) string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", p.Nesting))
+	switch p.Bullet {
+	case BulletDash:
+		b.WriteString("- ")
+	case BulletNumbered:
+		b.WriteString("1. ")
+	}
+	b.WriteString(markdownFragments(p.Fragments))
+	return b.String()
+}
+
+// markdownFragments renders the fragments as markdown. Consecutive fragments with the same link are
+// rendered as a single link, as a link spanning multiple text runs is split into a fragment per run.
+func markdownFragments(fragments []*Fragment) string {
+	var b strings.Builder
+	for i := 0; i < len(fragments); {
+		link := fragments[i].Link
+		j := i + 1
+		for link != "" && j < len(fragments) && fragments[j].Link == link {
+			j++
+		}
+		var text strings.Builder
+		for _, f := range fragments[i:j] {
+			text.WriteString(markdownFragment(f))
+		}
+		if link != "" {
+			fmt.Fprintf(&b, "[%s](%s)", text.String(), link)
+		} else {
+			b.WriteString(text.String())
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func markdownFragment(f *Fragment) string {
+	v := strings.ReplaceAll(f.Value, "\n", "<br>")
+	if f.Code {
+		v = "`" + v + "`"
+	}
+	if f.Bold {
+		v = "**" + v + "**"
+	}
+	if f.Italic {
+		v = "*" + v + "*"
+	}
+	return v
+}
+
+func markdownImage(i *Image) string {
+	md := fmt.Sprintf("![%s](%s)", i.alt, i.url)
+	if i.link != "" {
+		md = fmt.Sprintf("[%s](%s)", md, i.link)
+	}
+	return md
+}
@@ -0,0 +1,66 @@
+package deck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestConvertToSlideLinks(t *testing.T) {
+	png := dummyPNG(t).Bytes()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(png)
+	}))
+	t.Cleanup(ts.Close)
+
+	link := &slides.Link{Url: "https://example.com/"}
+	p := &slides.Page{
+		PageElements: []*slides.PageElement{{
+			Shape: &slides.Shape{
+				Placeholder: &slides.Placeholder{Type: "BODY"},
+				Text: &slides.TextContent{
+					TextElements: []*slides.TextElement{
+						{ParagraphMarker: &slides.ParagraphMarker{}},
+						{TextRun: &slides.TextRun{Content: "see "}},
+						// A link spanning multiple runs with different styles
+						{TextRun: &slides.TextRun{Content: "the ", Style: &slides.TextStyle{Link: link}}},
+						{TextRun: &slides.TextRun{Content: "docs", Style: &slides.TextStyle{Link: link, Bold: true}}},
+						{TextRun: &slides.TextRun{Content: "\n"}},
+					},
+				},
+			},
+		}, {
+			Image: &slides.Image{
+				ContentUrl:      ts.URL + "/image.png",
+				ImageProperties: &slides.ImageProperties{Link: &slides.Link{Url: "https://example.com/image"}},
+			},
+		}},
+	}
+
+	got := convertToSlide(p, nil)
+	want := []*Fragment{
+		{Value: "see "},
+		{Value: "the ", Link: "https://example.com/"},
+		{Value: "docs", Link: "https://example.com/", Bold: true},
+	}
+	if len(got.Bodies) != 1 || len(got.Bodies[0].Paragraphs) != 1 {
+		t.Fatalf("got %d bodies, want 1 body with 1 paragraph", len(got.Bodies))
+	}
+	if diff := cmp.Diff(want, got.Bodies[0].Paragraphs[0].Fragments); diff != "" {
+		t.Errorf("fragments mismatch (-want +got):\n%s", diff)
+	}
+	if len(got.Images) != 1 {
+		t.Fatalf("got %d images, want 1", len(got.Images))
+	}
+	if got.Images[0].link != "https://example.com/image" {
+		t.Errorf("got image link %q, want %q", got.Images[0].link, "https://example.com/image")
+	}
+
+	wantMarkdown := "see [the **docs**](https://example.com/)\n\n[![](" + ts.URL + "/image.png)](https://example.com/image)\n"
+	if diff := cmp.Diff(wantMarkdown, got.Markdown()); diff != "" {
+		t.Errorf("markdown mismatch (-want +got):\n%s", diff)
+	}
+}
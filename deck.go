@@ -5,13 +5,18 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"os"
+	"maps"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/k1LoW/deck/config"
 	"github.com/k1LoW/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/time/rate"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
 	"google.golang.org/api/slides/v1"
@@ -19,24 +24,77 @@ import (
 
 const layoutNameForStyle = "style"
 
+const mimeTypeFolder = "application/vnd.google-apps.folder"
+
+// defaultPresentationName is the name of a presentation created by Create or CreateFrom.
+const defaultPresentationName = "Untitled"
+
 var profileRe = regexp.MustCompile(`^[a-zA-Z0-9_-]*$`)
 
+// Deck is a Google Slides presentation to apply slides to.
+// It is safe for concurrent use by multiple goroutines; operations that read or modify
+// the presentation are serialized, so concurrent applies run one at a time.
 type Deck struct {
-	id                 string
-	profile            string
-	folderID           string
-	srv                *slides.Service
-	driveSrv           *drive.Service
-	presentation       *slides.Presentation
-	defaultTitleLayout string
-	defaultLayout      string
-	styles             map[string]*slides.TextStyle
-	shapes             map[string]*slides.ShapeProperties
-	tableStyle         *TableStyle
-	logger             *slog.Logger
-	fresh              bool
-	imageUploadCmd     string
-	imageDeleteCmd     string
+	// mu guards the presentation and the state derived from it, such as fresh, styles and default layouts.
+	mu                  sync.RWMutex
+	id                  string
+	profile             string
+	folderID            string
+	folderName          string
+	srv                 *slides.Service
+	driveSrv            *drive.Service
+	presentation        *slides.Presentation
+	defaultTitleLayout  string
+	defaultLayout       string
+	styles              map[string]*slides.TextStyle
+	shapes              map[string]*slides.ShapeProperties
+	tableStyle          *TableStyle
+	logger              *slog.Logger
+	fresh               bool
+	imageUploadCmd      string
+	imageDeleteCmd      string
+	managedStart        int
+	imageCacheEnabled   bool
+	imageCacheTTL       time.Duration
+	imageCache          *imageCache
+	concurrency         int
+	storage             Storage
+	storageChain        []Storage
+	fallbackStorage     *FallbackStorage // built from storageChain on first use, see getStorage
+	fallbackStorageMu   sync.Mutex
+	storageShared       bool // the storages are shared with sibling Decks and left to the caller to close
+	closeOnce           sync.Once
+	closeErr            error
+	maxImageDimension   int
+	wideTablePolicy     WideTablePolicy
+	wideTableMaxColumns int
+	svgScale            float64
+	flattenGIF          bool
+	contentAddressed    bool
+	imageLocks          sync.Map
+	requestTimeout      time.Duration
+	imageAnchor         ImageAnchor
+	dryRun              bool
+	batchSize           int
+	serviceAccountJSON  []byte
+	tokenSource         oauth2.TokenSource
+	allowEmpty          bool
+	onProgress          func(ProgressEvent)
+	title               string // cached title of the presentation, empty if not fetched yet
+	imageFetchTimeout   time.Duration
+	continueOnImgError  bool
+	metrics             *metrics // shared with the siblings, see stats
+	metricsOnce         sync.Once
+	defaultName         string
+	titleLayoutOverride string // layout for the first slide specified by WithDefaultTitleLayout
+	layoutOverride      string // layout for the other slides specified by WithDefaultLayout
+	strictIndices       bool
+	alwaysTrash         bool
+	permanentImages     bool
+	rateLimiter         *rate.Limiter
+	incrementalApply    bool
+	forceFullApply      bool
+	preserveEdited      bool
 }
 
 type Option func(*Deck) error
@@ -72,8 +130,368 @@ func WithFolderID(folderID string) Option {
 	}
 }
 
+// WithFolderName sets the folder by its name instead of its ID.
+// The ID is resolved once on initialization, and it is an error if no folder or more than one folder has the name.
+func WithFolderName(name string) Option {
+	return func(d *Deck) error {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("folder name is empty")
+		}
+		d.folderName = name
+		return nil
+	}
+}
+
+// WithManagedRange sets the index of the first slide managed by Apply.
+// Slides before start are left untouched, and Apply diffs, creates, and deletes slides only from start onward.
+func WithManagedRange(start int) Option {
+	return func(d *Deck) error {
+		if start < 0 {
+			return fmt.Errorf("invalid managed range start: %d", start)
+		}
+		d.managedStart = start
+		return nil
+	}
+}
+
+// WithImageCache enables or disables the on-disk cache of current images fetched from the presentation.
+// The cache is enabled by default.
+func WithImageCache(enabled bool) Option {
+	return func(d *Deck) error {
+		d.imageCacheEnabled = enabled
+		return nil
+	}
+}
+
+// WithImageCacheTTL sets the duration for which cached images are used without checking the remote.
+func WithImageCacheTTL(ttl time.Duration) Option {
+	return func(d *Deck) error {
+		if ttl <= 0 {
+			return fmt.Errorf("invalid image cache TTL: %s", ttl)
+		}
+		d.imageCacheTTL = ttl
+		return nil
+	}
+}
+
+// WithConcurrency sets the number of parallel workers for preloading, uploading, and cleaning up images.
+// The default is 4.
+func WithConcurrency(n int) Option {
+	return func(d *Deck) error {
+		if n < 1 {
+			return fmt.Errorf("invalid concurrency: %d", n)
+		}
+		d.concurrency = n
+		return nil
+	}
+}
+
+// WithMaxImageDimension sets the maximum length in pixels of the longest edge of images to upload.
+// Larger images are downscaled before upload, preserving the aspect ratio. Animated GIFs are not downscaled.
+func WithMaxImageDimension(px int) Option {
+	return func(d *Deck) error {
+		if px < 1 {
+			return fmt.Errorf("invalid max image dimension: %d", px)
+		}
+		d.maxImageDimension = px
+		return nil
+	}
+}
+
+// WithFlattenGIF enables uploading the first frame of animated GIFs as a static PNG image.
+// When disabled, animated GIFs are uploaded as they are.
+func WithFlattenGIF(enabled bool) Option {
+	return func(d *Deck) error {
+		d.flattenGIF = enabled
+		return nil
+	}
+}
+
+// WithWideTablePolicy sets the policy for tables with more columns than maxColumns.
+func WithWideTablePolicy(policy WideTablePolicy, maxColumns int) Option {
+	return func(d *Deck) error {
+		if err := validateWideTablePolicy(policy); err != nil {
+			return err
+		}
+		if maxColumns < 1 {
+			return fmt.Errorf("invalid max columns of wide tables: %d", maxColumns)
+		}
+		d.wideTablePolicy = policy
+		d.wideTableMaxColumns = maxColumns
+		return nil
+	}
+}
+
+// WithSVGScale sets the scale factor to rasterize SVG images at before upload.
+// SVG images are rasterized at the size of their viewBox multiplied by factor (1 is 96 DPI).
+func WithSVGScale(factor float64) Option {
+	return func(d *Deck) error {
+		if factor <= 0 {
+			return fmt.Errorf("invalid svg scale: %v", factor)
+		}
+		d.svgScale = factor
+		return nil
+	}
+}
+
+// WithImageAnchor sets where an image auto-placed on a slide sits within the content area (default: center).
+// The image is fitted into the content area preserving the aspect ratio.
+// Images placed in placeholders or replacing existing images are not affected.
+func WithImageAnchor(anchor ImageAnchor) Option {
+	return func(d *Deck) error {
+		if err := validateImageAnchor(anchor); err != nil {
+			return err
+		}
+		d.imageAnchor = anchor
+		return nil
+	}
+}
+
+// WithContentAddressedImages enables naming images uploaded to Google Drive by the SHA-256 of their content.
+// Identical images are uploaded once and shared across presentations, and are not deleted after apply.
+func WithContentAddressedImages(enabled bool) Option {
+	return func(d *Deck) error {
+		d.contentAddressed = enabled
+		return nil
+	}
+}
+
+// WithPermanentImages keeps images uploaded to Google Drive instead of deleting them after apply,
+// so that the images of the presentation do not depend on the cache of Google Slides.
+// The images are uploaded to the "deck-assets" subfolder of the folder, named by their content so that identical images are shared.
+func WithPermanentImages(enabled bool) Option {
+	return func(d *Deck) error {
+		d.permanentImages = enabled
+		return nil
+	}
+}
+
+// WithRequestTimeout sets the timeout for each call to the Google Slides and Google Drive APIs.
+// The timeout applies to each call, not to the whole operation. By default, calls do not time out.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(d *Deck) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid request timeout: %s", timeout)
+		}
+		d.requestTimeout = timeout
+		return nil
+	}
+}
+
+// WithIncrementalApply makes Apply skip the slides unchanged since the last apply to the unmodified presentation
+// with the same options. The content of the slides applied is stored in the state directory of the profile.
+// It is disabled by default.
+func WithIncrementalApply(enabled bool) Option {
+	return func(d *Deck) error {
+		d.incrementalApply = enabled
+		return nil
+	}
+}
+
+// WithForceFullApply makes Apply compare all the slides with the presentation even with WithIncrementalApply,
+// while still storing the content of the slides applied for the next apply.
+func WithForceFullApply(enabled bool) Option {
+	return func(d *Deck) error {
+		d.forceFullApply = enabled
+		return nil
+	}
+}
+
+// WithPreserveEditedSlides leaves the slides whose speaker notes contain "[deck:edited]" untouched on apply,
+// so that manual edits to them are not overwritten.
+func WithPreserveEditedSlides(enabled bool) Option {
+	return func(d *Deck) error {
+		d.preserveEdited = enabled
+		return nil
+	}
+}
+
+// WithRateLimit limits the calls to the Google Slides and Google Drive APIs to perSecond on average
+// with bursts of up to burst calls, shared across all calls made by the Deck. By default, calls are not limited.
+// Any minute allows perSecond*60 calls plus the burst, so for a project shared with other tools,
+// 0.9 calls per second with a burst of 1 stays under the default quota of 60 write requests per minute
+// per user of the Google Slides API.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(d *Deck) error {
+		if perSecond <= 0 {
+			return fmt.Errorf("invalid rate limit: %v", perSecond)
+		}
+		if burst < 1 {
+			return fmt.Errorf("invalid rate limit burst: %d", burst)
+		}
+		d.rateLimiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+		return nil
+	}
+}
+
+// WithServiceAccountJSON sets the service account key used for authentication.
+// It takes precedence over the credentials discovered from the environment variables and files.
+func WithServiceAccountJSON(jsonBytes []byte) Option {
+	return func(d *Deck) error {
+		if _, err := google.JWTConfigFromJSON(jsonBytes); err != nil {
+			return fmt.Errorf("invalid service account key: %w", err)
+		}
+		if d.tokenSource != nil {
+			return fmt.Errorf("service account key cannot be used with token source")
+		}
+		d.serviceAccountJSON = slices.Clone(jsonBytes)
+		return nil
+	}
+}
+
+// WithTokenSource sets the token source used for authentication, e.g., of Workload Identity Federation.
+// It takes precedence over the credentials discovered from the environment variables and files,
+// and cannot be used with WithServiceAccountJSON.
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(d *Deck) error {
+		if ts == nil {
+			return fmt.Errorf("token source is nil")
+		}
+		if len(d.serviceAccountJSON) > 0 {
+			return fmt.Errorf("token source cannot be used with service account key")
+		}
+		d.tokenSource = ts
+		return nil
+	}
+}
+
+// WithAllowEmpty allows applying empty slides, which clears the presentation down to one blank slide.
+// By default, applying empty slides returns ErrNoSlides to prevent wiping the presentation by accident.
+func WithAllowEmpty(enabled bool) Option {
+	return func(d *Deck) error {
+		d.allowEmpty = enabled
+		return nil
+	}
+}
+
+// WithTrashInsteadOfDelete makes the files on Google Drive, such as presentations and temporary images,
+// always moved to the trash instead of being deleted permanently, so that they can be recovered.
+func WithTrashInsteadOfDelete(enabled bool) Option {
+	return func(d *Deck) error {
+		d.alwaysTrash = enabled
+		return nil
+	}
+}
+
+// WithStrictIndices makes DeletePages return an error for out-of-range indices instead of skipping them.
+func WithStrictIndices(enabled bool) Option {
+	return func(d *Deck) error {
+		d.strictIndices = enabled
+		return nil
+	}
+}
+
+// WithDefaultTitleLayout sets the layout used for the first slide when it does not specify one.
+// It overrides the layout detected from the layout names, and must exist in the presentation.
+func WithDefaultTitleLayout(name string) Option {
+	return func(d *Deck) error {
+		if name == "" {
+			return errors.New("default title layout must not be empty")
+		}
+		d.titleLayoutOverride = name
+		return nil
+	}
+}
+
+// WithDefaultLayout sets the layout used for slides other than the first when they do not specify one.
+// It overrides the layout detected from the layout names, and must exist in the presentation.
+func WithDefaultLayout(name string) Option {
+	return func(d *Deck) error {
+		if name == "" {
+			return errors.New("default layout must not be empty")
+		}
+		d.layoutOverride = name
+		return nil
+	}
+}
+
+// WithDefaultName sets the name of the presentation file created by Create or CreateFrom.
+// The default is "Untitled".
+func WithDefaultName(name string) Option {
+	return func(d *Deck) error {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("default name must not be empty")
+		}
+		d.defaultName = name
+		return nil
+	}
+}
+
+// WithProgress sets the callback to receive progress events while applying slides.
+// The callback may be called concurrently from multiple goroutines, so it must be safe for concurrent use.
+func WithProgress(fn func(ProgressEvent)) Option {
+	return func(d *Deck) error {
+		d.onProgress = fn
+		return nil
+	}
+}
+
+// WithImageFetchTimeout sets the timeout for fetching each image from a URL.
+// It is independent of the timeout for the Google Slides and Google Drive APIs. The default is 60 seconds.
+func WithImageFetchTimeout(timeout time.Duration) Option {
+	return func(d *Deck) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid image fetch timeout: %s", timeout)
+		}
+		d.imageFetchTimeout = timeout
+		return nil
+	}
+}
+
+// WithContinueOnImageError sets whether to continue applying when some of the current images fail to be fetched.
+// The images that fail are replaced with new ones, and the errors are returned after the apply.
+func WithContinueOnImageError(enable bool) Option {
+	return func(d *Deck) error {
+		d.continueOnImgError = enable
+		return nil
+	}
+}
+
+// WithBatchSize sets the maximum number of requests in a single batch update.
+// Larger batches are split into chunks that are submitted in order. The default is 500.
+func WithBatchSize(n int) Option {
+	return func(d *Deck) error {
+		if n < 1 {
+			return fmt.Errorf("invalid batch size: %d", n)
+		}
+		d.batchSize = n
+		return nil
+	}
+}
+
+// WithDryRun enables the dry-run mode, in which Apply and ApplyPages only plan the changes
+// and no changes are made to the presentation.
+func WithDryRun(enabled bool) Option {
+	return func(d *Deck) error {
+		d.dryRun = enabled
+		return nil
+	}
+}
+
+// WithStorage sets the storage to upload images to.
+// It takes precedence over WithImageUploadCmd and Google Drive.
+func WithStorage(s Storage) Option {
+	return func(d *Deck) error {
+		d.storage = s
+		return nil
+	}
+}
+
+// WithStorageChain sets the storages to upload images to, tried in order until one of them succeeds.
+// The image upload command or Google Drive is tried last, so that images can still be uploaded when the others are unavailable.
+// WithStorage takes precedence over it.
+func WithStorageChain(storages ...Storage) Option {
+	return func(d *Deck) error {
+		if slices.Contains(storages, nil) {
+			return fmt.Errorf("storage chain must not contain nil")
+		}
+		d.storageChain = storages
+		return nil
+	}
+}
+
 // WithImageUploadCmd sets the command to upload images to external storage.
-// The command receives image data via stdin and the environment variable DECK_UPLOAD_MIME.
+// The command receives image data via stdin and the environment variables DECK_UPLOAD_MIME and DECK_UPLOAD_CACHE_CONTROL.
 // It should output the public URL on the first line and uploaded ID on the second line of stdout.
 func WithImageUploadCmd(cmd string) Option {
 	return func(d *Deck) error {
@@ -95,6 +513,7 @@ type placeholder struct {
 	objectID string
 	x        float64
 	y        float64
+	name     string // alt text title of the placeholder, used as the slot name of image placeholders
 }
 
 type bulletRange struct {
@@ -138,25 +557,25 @@ func Create(ctx context.Context, opts ...Option) (_ *Deck, err error) {
 	if err != nil {
 		return nil, err
 	}
-	title := "Untitled"
-	file := &drive.File{
-		Name:     title,
-		MimeType: "application/vnd.google-apps.presentation",
-	}
-	if d.folderID != "" {
-		file.Parents = []string{d.folderID}
-	}
-	f, err := d.driveSrv.Files.Create(file).SupportsAllDrives(true).Do()
-	if err != nil {
-		return nil, err
-	}
-	d.id = f.Id
-	if err := d.refresh(ctx); err != nil {
+	if err := d.create(ctx); err != nil {
 		return nil, err
 	}
 	return d, nil
 }
 
+// create creates a new presentation file and sets it as the target of the Deck.
+func (d *Deck) create(ctx context.Context) error {
+	var f *drive.File
+	if err := d.call(ctx, "create presentation", func(ctx context.Context) (err error) {
+		f, err = d.driveSrv.Files.Create(d.newPresentationFile()).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return err
+	}
+	d.id = f.Id
+	return d.refresh(ctx)
+}
+
 // CreateFrom creates a new Deck from the presentation ID.
 func CreateFrom(ctx context.Context, id string, opts ...Option) (_ *Deck, err error) {
 	defer func() {
@@ -167,15 +586,11 @@ func CreateFrom(ctx context.Context, id string, opts ...Option) (_ *Deck, err er
 		return nil, err
 	}
 	// copy presentation
-	file := &drive.File{
-		Name:     "Untitled",
-		MimeType: "application/vnd.google-apps.presentation",
-	}
-	if d.folderID != "" {
-		file.Parents = []string{d.folderID}
-	}
-	f, err := d.driveSrv.Files.Copy(id, file).SupportsAllDrives(true).Do()
-	if err != nil {
+	var f *drive.File
+	if err := d.call(ctx, "copy presentation", func(ctx context.Context) (err error) {
+		f, err = d.driveSrv.Files.Copy(id, d.newPresentationFile()).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
 		return nil, err
 	}
 	d.id = f.Id
@@ -195,6 +610,22 @@ func CreateFrom(ctx context.Context, id string, opts ...Option) (_ *Deck, err er
 	return d, nil
 }
 
+// newPresentationFile returns the metadata of a presentation file to be created.
+func (d *Deck) newPresentationFile() *drive.File {
+	name := d.defaultName
+	if name == "" {
+		name = defaultPresentationName
+	}
+	file := &drive.File{
+		Name:     name,
+		MimeType: "application/vnd.google-apps.presentation",
+	}
+	if d.folderID != "" {
+		file.Parents = []string{d.folderID}
+	}
+	return file
+}
+
 func Doctor(ctx context.Context, opts ...Option) error {
 	d, err := newDeck(ctx, opts...)
 	if err != nil {
@@ -204,8 +635,45 @@ func Doctor(ctx context.Context, opts ...Option) error {
 	return err
 }
 
+// Close releases the resources held by the Deck, such as the Storage if it implements io.Closer.
+// Callers that create many Decks in a long-running process should defer d.Close().
+// The Storage shared by the Decks created by CreateMultiple is not closed; the caller closes it.
+// Calling Close more than once returns the result of the first call.
+func (d *Deck) Close() error {
+	d.closeOnce.Do(func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.closeErr = d.close()
+	})
+	return d.closeErr
+}
+
+func (d *Deck) close() error {
+	if d.imageCache != nil {
+		d.imageCache.client.CloseIdleConnections()
+	}
+	if d.storageShared {
+		return nil
+	}
+	// The storages that deck builds itself hold no resources, so only the given ones are closed.
+	var errs []error
+	for _, storage := range append([]Storage{d.storage}, d.storageChain...) {
+		if c, ok := storage.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to close storage: %w", err)
+	}
+	return nil
+}
+
 // ID returns the ID of the presentation.
 func (d *Deck) ID() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	return d.id
 }
 
@@ -214,38 +682,136 @@ func (d *Deck) UpdateTitle(ctx context.Context, title string) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dryRun {
+		d.logger.Info("skip updating title because of dry-run", slog.String("title", title))
+		return nil
+	}
 	file := &drive.File{
 		Name: title,
 	}
-	if _, err := d.driveSrv.Files.Update(d.id, file).SupportsAllDrives(true).Context(ctx).Do(); err != nil {
+	if err := d.call(ctx, "update title", func(ctx context.Context) error {
+		_, err := d.driveSrv.Files.Update(d.id, file).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		d.title = ""
 		return err
 	}
+	d.title = title
 	return nil
 }
 
-// Export the presentation as PDF.
-func (d *Deck) Export(ctx context.Context, w io.Writer) (err error) {
+// Title returns the title of the presentation.
+// It reads only the name of the file from Google Drive instead of the whole presentation,
+// and caches it until the next operation that modifies the presentation.
+func (d *Deck) Title(ctx context.Context) (_ string, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
-	req, err := d.driveSrv.Files.Export(d.id, "application/pdf").Context(ctx).Download()
-	if err != nil {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.title != "" {
+		return d.title, nil
+	}
+	var f *drive.File
+	if err := d.call(ctx, "get title", func(ctx context.Context) (err error) {
+		f, err = d.driveSrv.Files.Get(d.id).Fields("name").SupportsAllDrives(true).Context(ctx).Do()
 		return err
+	}); err != nil {
+		return "", err
 	}
-	if err := req.Write(w); err != nil {
-		return fmt.Errorf("unable to create PDF file: %w", err)
+	d.title = f.Name
+	return d.title, nil
+}
+
+// SetFolder moves the presentation into the folder and uses the folder to upload temporary images to.
+func (d *Deck) SetFolder(ctx context.Context, folderID string) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var folder *drive.File
+	if err := d.call(ctx, "get folder", func(ctx context.Context) (err error) {
+		folder, err = d.driveSrv.Files.Get(folderID).Fields("id", "mimeType").SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to get folder %s: %w", folderID, err)
+	}
+	if folder.MimeType != mimeTypeFolder {
+		return fmt.Errorf("%s is not a folder", folderID)
+	}
+	var f *drive.File
+	if err := d.call(ctx, "get parents", func(ctx context.Context) (err error) {
+		f, err = d.driveSrv.Files.Get(d.id).Fields("parents").SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to get parents of presentation: %w", err)
+	}
+	var removeParents []string
+	for _, p := range f.Parents {
+		if p != folderID {
+			removeParents = append(removeParents, p)
+		}
 	}
+	if err := d.call(ctx, "move presentation", func(ctx context.Context) error {
+		_, err := d.driveSrv.Files.Update(d.id, &drive.File{}).AddParents(folderID).RemoveParents(strings.Join(removeParents, ",")).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to move presentation to folder %s: %w", folderID, err)
+	}
+	d.folderID = folderID
+	d.fallbackStorageMu.Lock()
+	if d.fallbackStorage != nil {
+		d.fallbackStorage.setLast(d.defaultStorage())
+	}
+	d.fallbackStorageMu.Unlock()
 	return nil
 }
 
+// Export the presentation as PDF.
+func (d *Deck) Export(ctx context.Context, w io.Writer) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.exportPDF(ctx, d.id, w)
+}
+
+// ExportTo exports the presentation as PDF to the file at path.
+// The PDF is streamed to a temporary file next to path, which replaces path only when the export succeeds,
+// so a failed export leaves no partial file behind.
+func (d *Deck) ExportTo(ctx context.Context, path string) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.exportPDFToFile(ctx, d.id, path)
+}
+
+// DeletePages deletes the pages at the indices. Out-of-range indices are skipped unless WithStrictIndices is set.
 func (d *Deck) DeletePages(ctx context.Context, indices []int) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deletePages(ctx, indices)
+}
+
+func (d *Deck) deletePages(ctx context.Context, indices []int) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
 
 	reqs := make([]*slides.Request, 0, len(indices))
+	var outOfRange []int
 	for _, idx := range indices {
-		if len(d.presentation.Slides) <= idx {
+		if idx < 0 || len(d.presentation.Slides) <= idx {
+			outOfRange = append(outOfRange, idx)
 			continue
 		}
 		currentSlide := d.presentation.Slides[idx]
@@ -255,11 +821,15 @@ func (d *Deck) DeletePages(ctx context.Context, indices []int) (err error) {
 			},
 		})
 	}
+	if d.strictIndices && len(outOfRange) > 0 {
+		return fmt.Errorf("page indices out of range (0-%d): %v", len(d.presentation.Slides)-1, outOfRange)
+	}
 	if len(reqs) > 0 {
 		d.logger.Info("deleting pages", slog.Any("indices", indices))
 		if err := d.batchUpdate(ctx, reqs); err != nil {
 			return fmt.Errorf("failed to delete pages: %w", err)
 		}
+		d.stats().slidesDeleted.Add(int64(len(reqs)))
 		if err := d.refresh(ctx); err != nil {
 			return fmt.Errorf("failed to refresh presentation after delete pages: %w", err)
 		}
@@ -272,6 +842,8 @@ func (d *Deck) DeletePageAfter(ctx context.Context, index int) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if len(d.presentation.Slides) <= index+1 {
 		return nil
 	}
@@ -287,6 +859,7 @@ func (d *Deck) DeletePageAfter(ctx context.Context, index int) (err error) {
 	if err := d.batchUpdate(ctx, reqs); err != nil {
 		return err
 	}
+	d.stats().slidesDeleted.Add(int64(len(reqs)))
 	if err := d.refresh(ctx); err != nil {
 		return err
 	}
@@ -297,15 +870,162 @@ func (d *Deck) MovePage(ctx context.Context, from_index, to_index int) (err erro
 	defer func() {
 		err = errors.WithStack(err)
 	}()
-	d.logger.Info("moving page", slog.Int("from_index", from_index), slog.Int("to_index", to_index))
-	if err := d.movePage(ctx, from_index, to_index); err != nil {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.movePage(ctx, from_index, to_index)
+}
+
+// ReorderPages reorders the slides of the presentation at once.
+// order is a permutation of the current slide indices, where order[i] is the current index of the slide
+// that should be placed at index i.
+// Only the slides that are out of place are moved, with a single batch update and a single refresh.
+func (d *Deck) ReorderPages(ctx context.Context, order []int) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	objectIDs := make([]string, len(d.presentation.Slides))
+	for i, s := range d.presentation.Slides {
+		objectIDs[i] = s.ObjectId
+	}
+	reqs, err := reorderRequests(objectIDs, order)
+	if err != nil {
 		return err
 	}
-	d.logger.Info("moved page", slog.Int("from_index", from_index), slog.Int("to_index", to_index))
+	if len(reqs) == 0 {
+		return nil
+	}
+	d.logger.Info("reordering pages", slog.Any("order", order), slog.Int("moves", len(reqs)))
+	if err := d.batchUpdate(ctx, reqs); err != nil {
+		return fmt.Errorf("failed to reorder pages: %w", err)
+	}
+	if err := d.refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh presentation after reorder pages: %w", err)
+	}
+	d.logger.Info("reordered pages", slog.Any("order", order), slog.Int("moves", len(reqs)))
 	return nil
 }
 
+// reorderRequests returns the UpdateSlidesPosition requests to reorder the slides of objectIDs by order.
+// The slides in the longest increasing subsequence of order keep their positions, and each of the other slides
+// is moved right after the slide that precedes it in the new order, so the number of moves is minimal.
+func reorderRequests(objectIDs []string, order []int) ([]*slides.Request, error) {
+	if len(order) != len(objectIDs) {
+		return nil, fmt.Errorf("invalid order: length %d does not match the number of slides %d", len(order), len(objectIDs))
+	}
+	seen := make([]bool, len(order))
+	for _, idx := range order {
+		if idx < 0 || idx >= len(order) || seen[idx] {
+			return nil, fmt.Errorf("invalid order: %v is not a permutation of 0..%d", order, len(order)-1)
+		}
+		seen[idx] = true
+	}
+
+	keep := make([]bool, len(order))
+	for _, i := range longestIncreasingSubsequence(order) {
+		keep[i] = true
+	}
+
+	current := slices.Clone(objectIDs)
+	var reqs []*slides.Request
+	for i, idx := range order {
+		if keep[i] {
+			continue
+		}
+		objectID := objectIDs[idx]
+		from := slices.Index(current, objectID)
+		to := 0
+		if i > 0 {
+			to = slices.Index(current, objectIDs[order[i-1]]) + 1
+		}
+		// The insertion index is based on the slide arrangement before the move takes place.
+		reqs = append(reqs, &slides.Request{
+			UpdateSlidesPosition: &slides.UpdateSlidesPositionRequest{
+				SlideObjectIds:  []string{objectID},
+				InsertionIndex:  int64(to),
+				ForceSendFields: []string{"InsertionIndex"},
+			},
+		})
+		current = slices.Delete(current, from, from+1)
+		if from < to {
+			to--
+		}
+		current = slices.Insert(current, to, objectID)
+	}
+	return reqs, nil
+}
+
+// longestIncreasingSubsequence returns the indices of a longest increasing subsequence of s.
+func longestIncreasingSubsequence(s []int) []int {
+	var (
+		tails []int // tails[k] is the index of the smallest tail of increasing subsequences of length k+1
+		prev  = make([]int, len(s))
+	)
+	for i, v := range s {
+		k, _ := slices.BinarySearchFunc(tails, v, func(idx, v int) int {
+			return s[idx] - v
+		})
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	lis := make([]int, len(tails))
+	if len(tails) == 0 {
+		return lis
+	}
+	i := tails[len(tails)-1]
+	for k := len(tails) - 1; k >= 0; k-- {
+		lis[k] = i
+		i = prev[i]
+	}
+	return lis
+}
+
+// SlideNotes returns the speaker notes of the slide at the index.
+// It returns an empty string if the slide has no notes.
+func (d *Deck) SlideNotes(ctx context.Context, index int) (_ string, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.refresh(ctx); err != nil {
+		return "", err
+	}
+	if index < 0 || len(d.presentation.Slides) <= index {
+		return "", fmt.Errorf("index out of range: %d", index)
+	}
+	return extractSpeakerNote(d.presentation.Slides[index]), nil
+}
+
+// AllSpeakerNotes returns the speaker notes of all slides in order of the slides.
+// Slides without notes have an empty string.
+func (d *Deck) AllSpeakerNotes(ctx context.Context) (_ []string, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.refresh(ctx); err != nil {
+		return nil, err
+	}
+	notes := make([]string, 0, len(d.presentation.Slides))
+	for _, s := range d.presentation.Slides {
+		notes = append(notes, extractSpeakerNote(s))
+	}
+	return notes, nil
+}
+
 // AllowReadingByAnyone sets the permission of the object to allow anyone to read it.
+// It does not lock the Deck, as the storage calls it to publish the images uploaded during Apply.
 func (d *Deck) AllowReadingByAnyone(ctx context.Context, objectID string) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -314,29 +1034,170 @@ func (d *Deck) AllowReadingByAnyone(ctx context.Context, objectID string) (err e
 		Type: "anyone",
 		Role: "reader",
 	}
-	if _, err := d.driveSrv.Permissions.Create(objectID, permission).SupportsAllDrives(true).Context(ctx).Do(); err != nil {
+	if err := d.call(ctx, "create permission", func(ctx context.Context) error {
+		_, err := d.driveSrv.Permissions.Create(objectID, permission).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
 		return fmt.Errorf("failed to set permission: %w", err)
 	}
 	return nil
 }
 
+// ShareWith grants the user of email the role ("reader", "commenter" or "writer") on the presentation.
+// If notify is true, Google Drive sends a notification email to the user.
+func (d *Deck) ShareWith(ctx context.Context, email, role string, notify bool) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.share(ctx, "user", email, role, notify)
+}
+
+// ShareWithGroup grants the group of email the role ("reader", "commenter" or "writer") on the presentation.
+// If notify is true, Google Drive sends a notification email to the members of the group.
+func (d *Deck) ShareWithGroup(ctx context.Context, email, role string, notify bool) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.share(ctx, "group", email, role, notify)
+}
+
+func (d *Deck) share(ctx context.Context, typ, email, role string, notify bool) error {
+	switch role {
+	case "reader", "commenter", "writer":
+	default:
+		return fmt.Errorf("invalid role: %q", role)
+	}
+	if email == "" {
+		return fmt.Errorf("email address is empty")
+	}
+	permission := &drive.Permission{
+		Type:         typ,
+		Role:         role,
+		EmailAddress: email,
+	}
+	if err := d.call(ctx, "create permission", func(ctx context.Context) error {
+		_, err := d.driveSrv.Permissions.Create(d.id, permission).SendNotificationEmail(notify).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to share presentation with %s: %w", email, err)
+	}
+	d.logger.Info("shared presentation", slog.String("email", email), slog.String("role", role))
+	return nil
+}
+
+// Permission is a permission on the presentation.
+type Permission struct {
+	ID           string
+	Type         string // "user", "group", "domain" or "anyone"
+	Role         string
+	EmailAddress string // for "user" and "group"
+	Domain       string // for "domain"
+}
+
+// Permissions returns the permissions on the presentation.
+func (d *Deck) Permissions(ctx context.Context) (_ []Permission, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.permissions(ctx)
+}
+
+func (d *Deck) permissions(ctx context.Context) ([]Permission, error) {
+	var (
+		permissions []Permission
+		pageToken   string
+	)
+	for {
+		var r *drive.PermissionList
+		if err := d.call(ctx, "list permissions", func(ctx context.Context) (err error) {
+			r, err = d.driveSrv.Permissions.List(d.id).SupportsAllDrives(true).
+				Fields("nextPageToken", "permissions(id, type, role, emailAddress, domain)").PageToken(pageToken).Context(ctx).Do()
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list permissions: %w", err)
+		}
+		for _, p := range r.Permissions {
+			permissions = append(permissions, Permission{
+				ID:           p.Id,
+				Type:         p.Type,
+				Role:         p.Role,
+				EmailAddress: p.EmailAddress,
+				Domain:       p.Domain,
+			})
+		}
+		if r.NextPageToken == "" {
+			break
+		}
+		pageToken = r.NextPageToken
+	}
+	return permissions, nil
+}
+
+// RevokePublicAccess deletes the permissions for anyone on the presentation, making it private again.
+// It does nothing if the presentation is not public.
+func (d *Deck) RevokePublicAccess(ctx context.Context) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	permissions, err := d.permissions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range permissions {
+		if p.Type != "anyone" {
+			continue
+		}
+		if err := d.call(ctx, "delete permission", func(ctx context.Context) error {
+			return d.driveSrv.Permissions.Delete(d.id, p.ID).SupportsAllDrives(true).Context(ctx).Do()
+		}); err != nil {
+			return fmt.Errorf("failed to revoke public access: %w", err)
+		}
+		d.logger.Info("revoked public access", slog.String("role", p.Role))
+	}
+	return nil
+}
+
 func newDeck(ctx context.Context, opts ...Option) (*Deck, error) {
+	d, err := newDeckWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = d.initialize(ctx)
+	return d, err
+}
+
+// newDeckWithOptions returns a Deck with the options applied, without the services initialized.
+func newDeckWithOptions(opts ...Option) (*Deck, error) {
 	d := &Deck{
-		styles:     map[string]*slides.TextStyle{},
-		shapes:     map[string]*slides.ShapeProperties{},
-		tableStyle: defaultTableStyle(),
+		styles:            map[string]*slides.TextStyle{},
+		shapes:            map[string]*slides.ShapeProperties{},
+		tableStyle:        defaultTableStyle(),
+		imageCacheEnabled: true,
+		imageCacheTTL:     defaultImageCacheTTL,
+		concurrency:       defaultConcurrency,
+		imageAnchor:       ImageAnchorCenter,
 	}
 	for _, opt := range opts {
 		if err := opt(d); err != nil {
 			return nil, err
 		}
 	}
-	err := d.initialize(ctx)
-	return d, err
+	return d, nil
 }
 
 var HTTPClientError = errors.New("http client error")
 
+// ErrNoSlides is returned when applying empty slides without WithAllowEmpty.
+var ErrNoSlides = errors.New("no slides to apply")
+
 func (d *Deck) initialize(ctx context.Context) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -344,15 +1205,19 @@ func (d *Deck) initialize(ctx context.Context) (err error) {
 	if d.logger == nil {
 		d.logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
 	}
-	if err := os.MkdirAll(config.StateHomePath(), 0700); err != nil {
+	if err := mkdirState(stateDir(d.profile)); err != nil {
 		return err
 	}
+	if d.imageCacheEnabled {
+		d.imageCache = newImageCache(filepath.Join(stateDir(d.profile), "images"), d.imageCacheTTL, d.imageFetchTimeoutOrDefault())
+	}
 
 	// Get client option (service account or OAuth2)
 	client, err := d.getHTTPClient(ctx)
 	if err != nil {
 		return errors.Join(err, HTTPClientError)
 	}
+	client.Transport = d.transport(client.Transport)
 
 	srv, err := slides.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
@@ -366,9 +1231,38 @@ func (d *Deck) initialize(ctx context.Context) (err error) {
 	}
 	driveSrv.UserAgent = userAgent
 	d.driveSrv = driveSrv
+	if d.folderName != "" && d.folderID == "" {
+		folderID, err := d.resolveFolderID(ctx, d.folderName)
+		if err != nil {
+			return err
+		}
+		d.folderID = folderID
+	}
 	return nil
 }
 
+// resolveFolderID returns the ID of the only folder with the name.
+func (d *Deck) resolveFolderID(ctx context.Context, name string) (string, error) {
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name)
+	q := fmt.Sprintf("name = '%s' and mimeType = '%s' and trashed = false", escaped, mimeTypeFolder)
+	var list *drive.FileList
+	if err := d.call(ctx, "find folder", func(ctx context.Context) (err error) {
+		list, err = d.driveSrv.Files.List().Q(q).Fields("files(id)").PageSize(2).
+			SupportsAllDrives(true).IncludeItemsFromAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to find folder %q: %w", name, err)
+	}
+	switch len(list.Files) {
+	case 0:
+		return "", fmt.Errorf("folder not found: %q", name)
+	case 1:
+		return list.Files[0].Id, nil
+	default:
+		return "", fmt.Errorf("more than one folder is named %q, use the folder ID instead", name)
+	}
+}
+
 func (d *Deck) createPage(ctx context.Context, index int, slide *Slide) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -393,6 +1287,7 @@ func (d *Deck) createPage(ctx context.Context, index int, slide *Slide) (err err
 	if err := d.batchUpdate(ctx, reqs); err != nil {
 		return fmt.Errorf("failed to create page: %w", err)
 	}
+	d.stats().slidesCreated.Add(1)
 	if err := d.refresh(ctx); err != nil {
 		return err
 	}
@@ -401,6 +1296,9 @@ func (d *Deck) createPage(ctx context.Context, index int, slide *Slide) (err err
 }
 
 // preparePages prepares the pages by creating slides with the specified layout IDs.
+// The slides are created with a single batch update, inserting the i-th slide at startIdx+i.
+// The Slides API applies the requests of a batch update in order and atomically,
+// so each slide is inserted after the previous one and the slides end up in the order of layoutIDs.
 func (d *Deck) preparePages(ctx context.Context, startIdx int, layoutIDs []string) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -410,6 +1308,7 @@ func (d *Deck) preparePages(ctx context.Context, startIdx int, layoutIDs []strin
 	for i, layoutID := range layoutIDs {
 		reqs[i] = &slides.Request{
 			CreateSlide: &slides.CreateSlideRequest{
+				ObjectId:       preparedPageID(d.presentation, startIdx, i, layoutID),
 				InsertionIndex: int64(slideIdx),
 				SlideLayoutReference: &slides.LayoutReference{
 					LayoutId: layoutID,
@@ -419,12 +1318,41 @@ func (d *Deck) preparePages(ctx context.Context, startIdx int, layoutIDs []strin
 		slideIdx++
 	}
 	if err := d.batchUpdate(ctx, reqs); err != nil {
-		return err
+		// The slides may have been created even though the batch update failed,
+		// e.g. when the response was lost. Create only the slides that do not exist yet.
+		if rerr := d.refresh(ctx); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		var remaining []*slides.Request
+		for _, req := range reqs {
+			if !slices.ContainsFunc(d.presentation.Slides, func(s *slides.Page) bool {
+				return s.ObjectId == req.CreateSlide.ObjectId
+			}) {
+				remaining = append(remaining, req)
+			}
+		}
+		if len(remaining) == len(reqs) {
+			return err
+		}
+		d.logger.Info("retrying to prepare pages not created yet", slog.Int("count", len(remaining)))
+		if len(remaining) > 0 {
+			if err := d.batchUpdate(ctx, remaining); err != nil {
+				return err
+			}
+		}
 	}
+	d.stats().slidesCreated.Add(int64(len(layoutIDs)))
 	d.logger.Debug("prepared pages", slog.Int("count", len(layoutIDs)), slog.Int("start_index", startIdx))
 	return d.refresh(ctx)
 }
 
+// preparedPageID returns the object ID of the slide created by preparePages.
+// The ID is deterministic for the revision of the presentation, so that retried requests do not duplicate slides.
+func preparedPageID(p *slides.Presentation, startIdx, i int, layoutID string) string {
+	key := fmt.Sprintf("%s/%s/%d/%d/%d/%s", p.PresentationId, p.RevisionId, len(p.Slides), startIdx, i, layoutID)
+	return "deck-" + hashHex([]byte(key))[:32]
+}
+
 func (d *Deck) movePage(ctx context.Context, from_index, to_index int) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -432,6 +1360,7 @@ func (d *Deck) movePage(ctx context.Context, from_index, to_index int) (err erro
 	if from_index == to_index || from_index < 0 || to_index < 0 || from_index >= len(d.presentation.Slides) || to_index >= len(d.presentation.Slides) {
 		return nil
 	}
+	d.logger.Info("moving page", slog.Int("from_index", from_index), slog.Int("to_index", to_index))
 
 	currentSlide := d.presentation.Slides[from_index]
 
@@ -452,6 +1381,7 @@ func (d *Deck) movePage(ctx context.Context, from_index, to_index int) (err erro
 	if err := d.refresh(ctx); err != nil {
 		return err
 	}
+	d.logger.Info("moved page", slog.Int("from_index", from_index), slog.Int("to_index", to_index))
 	return nil
 }
 
@@ -463,8 +1393,144 @@ func (d *Deck) layoutMap() map[string]*slides.Page {
 	return layoutMap
 }
 
+// Layouts returns the sorted display names of the layouts of the presentation.
+// It does not fetch the presentation, so it reflects the last refresh.
+func (d *Deck) Layouts() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.presentation == nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(d.layoutMap()))
+}
+
+// SlideCount returns the number of slides of the presentation.
+// It does not fetch the presentation, so it reflects the last refresh.
+func (d *Deck) SlideCount() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.presentation == nil {
+		return 0
+	}
+	return len(d.presentation.Slides)
+}
+
+// SlideLayout returns the display name of the layout of the slide at index.
+// It does not fetch the presentation, so it reflects the last refresh.
+func (d *Deck) SlideLayout(index int) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	count := 0
+	if d.presentation != nil {
+		count = len(d.presentation.Slides)
+	}
+	if index < 0 || count <= index {
+		return "", fmt.Errorf("page index out of range (0-%d): %d", count-1, index)
+	}
+	p := d.presentation.Slides[index]
+	if p.SlideProperties == nil {
+		return "", nil
+	}
+	for _, l := range d.presentation.Layouts {
+		if l.ObjectId == p.SlideProperties.LayoutObjectId && l.LayoutProperties != nil {
+			return l.LayoutProperties.DisplayName, nil
+		}
+	}
+	return "", nil
+}
+
+// StyleNames returns the sorted names of the styles defined in the "style" layout.
+// It does not fetch the presentation, so it reflects the last refresh.
+func (d *Deck) StyleNames() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return slices.Sorted(maps.Keys(d.styles))
+}
+
+// UnusedStyles returns the sorted names of the styles defined in the "style" layout that none of ss uses.
+// It works in memory, so it reflects the styles of the last refresh.
+func (d *Deck) UnusedStyles(ss Slides) []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	used := map[string]struct{}{}
+	for _, slide := range ss {
+		if slide == nil {
+			continue
+		}
+		if len(slide.BlockQuotes) > 0 {
+			used[styleBlockQuote] = struct{}{}
+		}
+		for _, f := range slideFragments(slide) {
+			if f.Code {
+				used[styleCode] = struct{}{}
+			}
+			if f.Bold {
+				used[styleBold] = struct{}{}
+			}
+			if f.Italic {
+				used[styleItalic] = struct{}{}
+			}
+			if f.Link != "" {
+				used[styleLink] = struct{}{}
+			}
+			if f.StyleName != "" {
+				used[f.StyleName] = struct{}{}
+			}
+		}
+	}
+	var unused []string
+	for _, name := range slices.Sorted(maps.Keys(d.styles)) {
+		if _, ok := used[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	return unused
+}
+
+// slideFragments returns the non-nil fragments of the bodies, block quotes and table cells of the slide.
+func slideFragments(slide *Slide) []*Fragment {
+	var fragments []*Fragment
+	addFragments := func(fs []*Fragment) {
+		for _, f := range fs {
+			if f != nil {
+				fragments = append(fragments, f)
+			}
+		}
+	}
+	addParagraphs := func(paragraphs []*Paragraph) {
+		for _, p := range paragraphs {
+			if p != nil {
+				addFragments(p.Fragments)
+			}
+		}
+	}
+	for _, bodies := range [][]*Body{slide.TitleBodies, slide.SubtitleBodies, slide.Bodies} {
+		for _, b := range bodies {
+			if b != nil {
+				addParagraphs(b.Paragraphs)
+			}
+		}
+	}
+	for _, bq := range slide.BlockQuotes {
+		if bq != nil {
+			addParagraphs(bq.Paragraphs)
+		}
+	}
+	for _, t := range slide.Tables {
+		if t == nil {
+			continue
+		}
+		for _, r := range t.Rows {
+			for _, c := range r.Cells {
+				addFragments(c.Fragments)
+			}
+		}
+	}
+	return fragments
+}
+
 // validateLayouts validates that all layouts used in slides exist in the presentation.
-// It returns an error if any layout is not found, with available layouts listed in the error message.
+// It returns a LayoutNotFoundError listing the available layouts if any layout is not found.
 func (d *Deck) validateLayouts(ss Slides) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -472,32 +1538,60 @@ func (d *Deck) validateLayouts(ss Slides) (err error) {
 	layoutMap := d.layoutMap()
 	var notFound []string
 	for i, slide := range ss {
-		layout := slide.Layout
-		if layout == "" {
-			// Use default layout if not specified
-			if i == 0 {
-				layout = d.defaultTitleLayout
-			} else {
-				layout = d.defaultLayout
-			}
-		}
+		layout := d.layoutOrDefault(i, slide.Layout)
 		if _, ok := layoutMap[layout]; !ok {
 			notFound = append(notFound, layout)
 		}
 	}
 	if len(notFound) > 0 {
-		slices.Sort(notFound)
-		notFound = slices.Compact(notFound)
-		var available []string
-		for name := range layoutMap {
-			available = append(available, name)
-		}
-		slices.Sort(available)
-		return fmt.Errorf("layout not found: %q\navailable layouts: %v", notFound, available)
+		return newLayoutNotFoundError(notFound, layoutMap)
 	}
 	return nil
 }
 
+// layoutOrDefault returns layout, or the default layout for the slide at index i if layout is empty.
+func (d *Deck) layoutOrDefault(i int, layout string) string {
+	if layout != "" {
+		return layout
+	}
+	if i == 0 {
+		return d.defaultTitleLayout
+	}
+	return d.defaultLayout
+}
+
+// blankLayout returns the display name of the blank layout, or the default title layout if there is none.
+func (d *Deck) blankLayout() string {
+	for _, l := range d.presentation.Layouts {
+		if l.LayoutProperties != nil && strings.HasPrefix(l.LayoutProperties.Name, "BLANK") {
+			return l.LayoutProperties.DisplayName
+		}
+	}
+	return d.defaultTitleLayout
+}
+
+// newLayoutNotFoundError returns a LayoutNotFoundError for the layouts not found in layoutMap.
+func newLayoutNotFoundError(notFound []string, layoutMap map[string]*slides.Page) *LayoutNotFoundError {
+	slices.Sort(notFound)
+	notFound = slices.Compact(notFound)
+	var available []string
+	for name := range layoutMap {
+		available = append(available, name)
+	}
+	slices.Sort(available)
+	return &LayoutNotFoundError{Layouts: notFound, Available: available}
+}
+
+// LayoutNotFoundError is the error returned when slides use layouts that the presentation does not have.
+type LayoutNotFoundError struct {
+	Layouts   []string // layouts not found
+	Available []string // layouts of the presentation
+}
+
+func (e *LayoutNotFoundError) Error() string {
+	return fmt.Sprintf("layout not found: %q\navailable layouts: %v", e.Layouts, e.Available)
+}
+
 func (d *Deck) refresh(ctx context.Context) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
@@ -505,11 +1599,16 @@ func (d *Deck) refresh(ctx context.Context) (err error) {
 	if d.fresh {
 		return nil
 	}
-	presentation, err := d.srv.Presentations.Get(d.id).Context(ctx).Do()
-	if err != nil {
+	var presentation *slides.Presentation
+	if err := d.call(ctx, "get presentation", func(ctx context.Context) (err error) {
+		presentation, err = d.srv.Presentations.Get(d.id).Context(ctx).Do()
+		return err
+	}); err != nil {
+		d.stats().addError(ErrorTypeRefresh)
 		return err
 	}
 	d.presentation = presentation
+	d.newProgress(ProgressPhaseRefreshing, 1).done(-1)
 
 	// set default layouts and detect style
 	for _, l := range d.presentation.Layouts {
@@ -569,24 +1668,51 @@ func (d *Deck) refresh(ctx context.Context) (err error) {
 			d.defaultLayout = d.presentation.Layouts[0].LayoutProperties.DisplayName
 		}
 	}
+
+	// Layouts specified by options take precedence over the detected ones.
+	var notFound []string
+	if d.titleLayoutOverride != "" {
+		if _, ok := layoutMap[d.titleLayoutOverride]; ok {
+			d.defaultTitleLayout = d.titleLayoutOverride
+		} else {
+			notFound = append(notFound, d.titleLayoutOverride)
+		}
+	}
+	if d.layoutOverride != "" {
+		if _, ok := layoutMap[d.layoutOverride]; ok {
+			d.defaultLayout = d.layoutOverride
+		} else {
+			notFound = append(notFound, d.layoutOverride)
+		}
+	}
+	if len(notFound) > 0 {
+		return newLayoutNotFoundError(notFound, layoutMap)
+	}
 	d.fresh = true
 	return nil
 }
 
 // deleteOrTrashFile attempts to delete a file, or move it to trash if deletion is not allowed.
 func (d *Deck) deleteOrTrashFile(ctx context.Context, id string) error {
-	file, err := d.driveSrv.Files.Get(id).SupportsAllDrives(true).Fields("capabilities").Context(ctx).Do()
-	if err != nil {
+	var file *drive.File
+	if err := d.call(ctx, "get file", func(ctx context.Context) (err error) {
+		file, err = d.driveSrv.Files.Get(id).SupportsAllDrives(true).Fields("capabilities").Context(ctx).Do()
+		return err
+	}); err != nil {
 		return fmt.Errorf("file not found or not accessible before deletion (file ID: %s): %w", id, err)
 	}
 
-	if file.Capabilities == nil || file.Capabilities.CanDelete {
-		return d.driveSrv.Files.Delete(id).SupportsAllDrives(true).Context(ctx).Do()
+	if !d.alwaysTrash && (file.Capabilities == nil || file.Capabilities.CanDelete) {
+		return d.call(ctx, "delete file", func(ctx context.Context) error {
+			return d.driveSrv.Files.Delete(id).SupportsAllDrives(true).Context(ctx).Do()
+		})
 	}
-	if file.Capabilities.CanTrash {
+	if file.Capabilities == nil || file.Capabilities.CanTrash {
 		updateRequest := &drive.File{Trashed: true}
-		_, err := d.driveSrv.Files.Update(id, updateRequest).SupportsAllDrives(true).Context(ctx).Do()
-		if err != nil {
+		if err := d.call(ctx, "trash file", func(ctx context.Context) error {
+			_, err := d.driveSrv.Files.Update(id, updateRequest).SupportsAllDrives(true).Context(ctx).Do()
+			return err
+		}); err != nil {
 			return fmt.Errorf("failed to trash presentation: %w", err)
 		}
 		return nil
@@ -596,8 +1722,38 @@ func (d *Deck) deleteOrTrashFile(ctx context.Context, id string) error {
 
 // getStorage returns the appropriate Storage based on configuration.
 func (d *Deck) getStorage() Storage {
+	if d.storage != nil {
+		return d.storage
+	}
+	if len(d.storageChain) > 0 {
+		// The same FallbackStorage must be used for uploading and deleting,
+		// since it remembers which of the storages uploaded each image.
+		d.fallbackStorageMu.Lock()
+		defer d.fallbackStorageMu.Unlock()
+		if d.fallbackStorage == nil {
+			d.fallbackStorage = NewFallbackStorage(append(slices.Clone(d.storageChain), d.defaultStorage())...)
+			if d.logger != nil {
+				d.fallbackStorage.logger = d.logger
+			}
+		}
+		return d.fallbackStorage
+	}
+	return d.defaultStorage()
+}
+
+// defaultStorage returns the storage that the image upload command or Google Drive implements.
+func (d *Deck) defaultStorage() Storage {
 	if d.imageUploadCmd != "" {
 		return newExternalStorage(d.imageUploadCmd, d.imageDeleteCmd)
 	}
-	return newGoogleDriveStorage(d.driveSrv, d.folderID, d.AllowReadingByAnyone, d.deleteOrTrashFile)
+	s := newGoogleDriveStorage(d.driveSrv, d.folderID, d.AllowReadingByAnyone, d.deleteOrTrashFile)
+	s.requestTimeout = d.requestTimeout
+	if d.contentAddressed || d.permanentImages {
+		s.contentAddressed = true
+		s.locks = &d.imageLocks
+	}
+	if d.permanentImages {
+		s.assetsFolderName = assetsFolderName
+	}
+	return s
 }
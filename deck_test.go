@@ -1,9 +1,21 @@
 package deck
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"golang.org/x/oauth2"
+	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/slides/v1"
 )
 
@@ -175,6 +187,109 @@ func TestWithProfile(t *testing.T) {
 	}
 }
 
+func TestWithConcurrency(t *testing.T) {
+	tests := []struct {
+		n           int
+		want        int
+		expectError bool
+	}{
+		{n: 1, want: 1},
+		{n: 16, want: 16},
+		{n: 0, expectError: true},
+		{n: -1, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d", tt.n), func(t *testing.T) {
+			deck := &Deck{}
+			err := WithConcurrency(tt.n)(deck)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("WithConcurrency(%d) expected error but got none", tt.n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WithConcurrency(%d) expected no error but got: %v", tt.n, err)
+			}
+			if got := deck.workersNum(); got != tt.want {
+				t.Errorf("got %d workers, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := (&Deck{}).workersNum(); got != defaultConcurrency {
+		t.Errorf("got %d workers for unset concurrency, want %d", got, defaultConcurrency)
+	}
+}
+
+func TestWithServiceAccountJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		expectError bool
+	}{
+		{name: "service account key", in: `{"type":"service_account","client_email":"deck@example.iam.gserviceaccount.com","private_key":"key","token_uri":"https://oauth2.googleapis.com/token"}`},
+		{name: "not JSON", in: "invalid", expectError: true},
+		{name: "empty", in: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deck := &Deck{}
+			err := WithServiceAccountJSON([]byte(tt.in))(deck)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if string(deck.serviceAccountJSON) != tt.in {
+				t.Errorf("got %q, want %q", deck.serviceAccountJSON, tt.in)
+			}
+		})
+	}
+}
+
+func TestWithTokenSource(t *testing.T) {
+	const key = `{"type":"service_account","client_email":"deck@example.iam.gserviceaccount.com","private_key":"key","token_uri":"https://oauth2.googleapis.com/token"}`
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "federated"})
+	if _, err := newDeckWithOptions(WithTokenSource(ts), WithServiceAccountJSON([]byte(key))); err == nil {
+		t.Error("expected error for token source with service account key but got none")
+	}
+	if _, err := newDeckWithOptions(WithServiceAccountJSON([]byte(key)), WithTokenSource(ts)); err == nil {
+		t.Error("expected error for service account key with token source but got none")
+	}
+	if _, err := newDeckWithOptions(WithTokenSource(nil)); err == nil {
+		t.Error("expected error for nil token source but got none")
+	}
+
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(srv.Close)
+	d, err := newDeckWithOptions(WithTokenSource(ts), WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := d.getHTTPClient(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if got != "Bearer federated" {
+		t.Errorf("got Authorization %q, want %q", got, "Bearer federated")
+	}
+}
+
 func TestValidateLayouts(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -305,3 +420,726 @@ func TestValidateLayouts(t *testing.T) {
 		})
 	}
 }
+
+func TestReorderRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     []int
+		wantMoves int
+		wantErr   bool
+	}{
+		{"identity", []int{0, 1, 2, 3, 4}, 0, false},
+		{"swap adjacent", []int{1, 0, 2, 3, 4}, 1, false},
+		{"move last to first", []int{4, 0, 1, 2, 3}, 1, false},
+		{"move first to last", []int{1, 2, 3, 4, 0}, 1, false},
+		{"reverse", []int{4, 3, 2, 1, 0}, 4, false},
+		{"shuffle", []int{2, 0, 4, 1, 3}, 2, false},
+		{"empty", []int{}, 0, false},
+		{"length mismatch", []int{0, 1}, 0, true},
+		{"duplicate index", []int{0, 1, 1, 3, 4}, 0, true},
+		{"out of range", []int{0, 1, 2, 3, 5}, 0, true},
+		{"negative", []int{-1, 1, 2, 3, 4}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objectIDs := []string{"a", "b", "c", "d", "e"}
+			if tt.name == "empty" {
+				objectIDs = []string{}
+			}
+			reqs, err := reorderRequests(objectIDs, tt.order)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(reqs) != tt.wantMoves {
+				t.Errorf("got %d moves, want %d", len(reqs), tt.wantMoves)
+			}
+			// Simulate the requests. The insertion index is based on the arrangement before the move.
+			got := slices.Clone(objectIDs)
+			for _, r := range reqs {
+				id := r.UpdateSlidesPosition.SlideObjectIds[0]
+				from := slices.Index(got, id)
+				to := int(r.UpdateSlidesPosition.InsertionIndex)
+				got = slices.Delete(got, from, from+1)
+				if from < to {
+					to--
+				}
+				got = slices.Insert(got, to, id)
+			}
+			want := make([]string, len(tt.order))
+			for i, idx := range tt.order {
+				want[i] = objectIDs[idx]
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetFolder(t *testing.T) {
+	parents := map[string][]string{"fake-presentation": {"root"}}
+	mimeTypes := map[string]string{
+		"fake-presentation": "application/vnd.google-apps.presentation",
+		"folder":            "application/vnd.google-apps.folder",
+		"root":              "application/vnd.google-apps.folder",
+	}
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			id := strings.TrimPrefix(r.URL.Path, "/drive/v3/files/")
+			mimeType, ok := mimeTypes[id]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Method == http.MethodPatch {
+				q := r.URL.Query()
+				var ps []string
+				for _, p := range parents[id] {
+					if !slices.Contains(strings.Split(q.Get("removeParents"), ","), p) {
+						ps = append(ps, p)
+					}
+				}
+				parents[id] = append(ps, q.Get("addParents"))
+			}
+			_ = json.NewEncoder(w).Encode(&drive.File{Id: id, MimeType: mimeType, Parents: parents[id]})
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	if err := d.SetFolder(t.Context(), "missing"); err == nil {
+		t.Error("expected error for missing folder but got none")
+	}
+	if err := d.SetFolder(t.Context(), "fake-presentation"); err == nil {
+		t.Error("expected error for non-folder but got none")
+	}
+	if err := d.SetFolder(t.Context(), "folder"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"folder"}; !slices.Equal(parents["fake-presentation"], want) {
+		t.Errorf("got parents %v, want %v", parents["fake-presentation"], want)
+	}
+	if d.folderID != "folder" {
+		t.Errorf("got folderID %q, want %q", d.folderID, "folder")
+	}
+	if storage, ok := d.getStorage().(*googleDriveStorage); !ok || storage.folderID != "folder" {
+		t.Error("storage should upload images to the new folder")
+	}
+}
+
+func TestSlideNotes(t *testing.T) {
+	p := newFakePresentation("a", "b")
+	p.Slides[0].SlideProperties.NotesPage.PageElements[0].Shape.Text = &slides.TextContent{
+		TextElements: []*slides.TextElement{{
+			TextRun: &slides.TextRun{Content: "first line\v"},
+		}, {
+			TextRun: &slides.TextRun{Content: "second line\n"},
+		}},
+	}
+	s := &fakeServer{presentation: p}
+	d := newFakeDeck(t, s)
+
+	got, err := d.SlideNotes(t.Context(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "first line\nsecond line"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, err = d.SlideNotes(t.Context(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("got %q, want empty notes", got)
+	}
+
+	if _, err := d.SlideNotes(t.Context(), 2); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestAllSpeakerNotes(t *testing.T) {
+	p := newFakePresentation("a", "b", "c")
+	p.Slides[0].SlideProperties.NotesPage.PageElements[0].Shape.Text = &slides.TextContent{
+		TextElements: []*slides.TextElement{{
+			TextRun: &slides.TextRun{Content: "note of a\n"},
+		}},
+	}
+	// A slide without notes page.
+	p.Slides[2].SlideProperties.NotesPage = nil
+	s := &fakeServer{presentation: p}
+	d := newFakeDeck(t, s)
+
+	got, err := d.AllSpeakerNotes(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"note of a", "", ""}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSlideNotesWithSpeakerNotesObjectID(t *testing.T) {
+	p := newFakePresentation("a")
+	notesPage := p.Slides[0].SlideProperties.NotesPage
+	// The body placeholder is not the speaker notes shape.
+	notesPage.PageElements[0].Shape.Text = &slides.TextContent{
+		TextElements: []*slides.TextElement{{TextRun: &slides.TextRun{Content: "not notes\n"}}},
+	}
+	notesPage.PageElements = append(notesPage.PageElements, &slides.PageElement{
+		ObjectId: "speaker-notes",
+		Shape: &slides.Shape{Text: &slides.TextContent{
+			TextElements: []*slides.TextElement{{TextRun: &slides.TextRun{Content: "notes\n"}}},
+		}},
+	})
+	notesPage.NotesProperties = &slides.NotesProperties{SpeakerNotesObjectId: "speaker-notes"}
+	s := &fakeServer{presentation: p}
+	d := newFakeDeck(t, s)
+
+	got, err := d.SlideNotes(t.Context(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "notes" {
+		t.Errorf("got %q, want %q", got, "notes")
+	}
+}
+
+func TestPreparePagesRetry(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	// The first batch update creates the slides, but the response is lost.
+	s.batchUpdateHook = func(req *slides.BatchUpdatePresentationRequest) error {
+		if len(s.batchUpdates) > 1 {
+			return nil
+		}
+		for _, r := range req.Requests {
+			s.presentation.Slides = append(s.presentation.Slides, &slides.Page{
+				ObjectId:        r.CreateSlide.ObjectId,
+				SlideProperties: &slides.SlideProperties{LayoutObjectId: r.CreateSlide.SlideLayoutReference.LayoutId},
+			})
+		}
+		return fmt.Errorf("connection reset")
+	}
+	d := newFakeDeck(t, s)
+	if err := d.refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.preparePages(t.Context(), 1, []string{"layout-body", "layout-body"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.batchUpdates) != 1 {
+		t.Errorf("got %d batch updates, want 1; created slides should not be created again", len(s.batchUpdates))
+	}
+	if got := len(d.presentation.Slides); got != 3 {
+		t.Errorf("got %d slides, want 3", got)
+	}
+	ids := map[string]bool{}
+	for _, r := range s.batchUpdates[0].Requests {
+		id := r.CreateSlide.ObjectId
+		if id == "" || ids[id] {
+			t.Errorf("slides should have unique object IDs: %q", id)
+		}
+		ids[id] = true
+	}
+}
+
+func TestTitle(t *testing.T) {
+	name := "original"
+	var gets int
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				gets++
+				if got := r.URL.Query().Get("fields"); got != "name" {
+					t.Errorf("got fields %q, want %q", got, "name")
+				}
+			case http.MethodPatch:
+				f := &drive.File{}
+				if err := json.NewDecoder(r.Body).Decode(f); err != nil {
+					t.Fatal(err)
+				}
+				name = f.Name
+			}
+			_ = json.NewEncoder(w).Encode(&drive.File{Name: name})
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	for range 2 {
+		title, err := d.Title(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if title != "original" {
+			t.Errorf("got %q, want %q", title, "original")
+		}
+	}
+	if gets != 1 {
+		t.Errorf("got %d requests to get the title, want 1", gets)
+	}
+
+	if err := d.UpdateTitle(t.Context(), "updated"); err != nil {
+		t.Fatal(err)
+	}
+	title, err := d.Title(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "updated" {
+		t.Errorf("got %q, want %q", title, "updated")
+	}
+	if gets != 1 {
+		t.Errorf("got %d requests to get the title, want 1", gets)
+	}
+}
+
+func TestCreateWithDefaultName(t *testing.T) {
+	if err := WithDefaultName(" ")(&Deck{}); err == nil {
+		t.Error("expected error for empty default name but got none")
+	}
+
+	tests := []struct {
+		opts []Option
+		want string
+	}{
+		{nil, "Untitled"},
+		{[]Option{WithDefaultName("Weekly Report")}, "Weekly Report"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var got string
+			s := &fakeServer{
+				presentation: newFakePresentation("a"),
+				driveHandler: func(w http.ResponseWriter, r *http.Request) {
+					f := &drive.File{}
+					if err := json.NewDecoder(r.Body).Decode(f); err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					got = f.Name
+					_ = json.NewEncoder(w).Encode(&drive.File{Id: "fake-presentation"})
+				},
+			}
+			d := newFakeDeck(t, s, tt.opts...)
+			if err := d.create(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshWithDefaultLayouts(t *testing.T) {
+	t.Run("override detected layouts", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("a")}
+		d := newFakeDeck(t, s, WithDefaultTitleLayout("Title and Content"), WithDefaultLayout("Title Slide"))
+		if err := d.refresh(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if d.defaultTitleLayout != "Title and Content" {
+			t.Errorf("defaultTitleLayout = %q, want %q", d.defaultTitleLayout, "Title and Content")
+		}
+		if d.defaultLayout != "Title Slide" {
+			t.Errorf("defaultLayout = %q, want %q", d.defaultLayout, "Title Slide")
+		}
+	})
+
+	t.Run("layout not found", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("a")}
+		d := newFakeDeck(t, s, WithDefaultLayout("Missing"))
+		err := d.refresh(t.Context())
+		var lerr *LayoutNotFoundError
+		if !errors.As(err, &lerr) {
+			t.Fatalf("expected LayoutNotFoundError but got %v", err)
+		}
+		if !slices.Equal(lerr.Layouts, []string{"Missing"}) {
+			t.Errorf("Layouts = %v, want [Missing]", lerr.Layouts)
+		}
+		if !slices.Equal(lerr.Available, []string{"Title Slide", "Title and Content"}) {
+			t.Errorf("Available = %v", lerr.Available)
+		}
+	})
+}
+
+func TestDeletePagesStrictIndices(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a", "b")}
+	d := newFakeDeck(t, s, WithStrictIndices(true))
+	if err := d.refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	err := d.DeletePages(t.Context(), []int{1, 2, -1})
+	if err == nil {
+		t.Fatal("expected error for out-of-range indices but got none")
+	}
+	if !strings.Contains(err.Error(), "[2 -1]") {
+		t.Errorf("error does not list out-of-range indices: %v", err)
+	}
+	if len(s.batchUpdates) != 0 {
+		t.Errorf("expected no batch updates, got %d", len(s.batchUpdates))
+	}
+
+	d = newFakeDeck(t, s)
+	if err := d.refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DeletePages(t.Context(), []int{2}); err != nil {
+		t.Errorf("expected out-of-range index to be skipped, got %v", err)
+	}
+}
+
+func TestLayoutsAndStyleNames(t *testing.T) {
+	p := newFakePresentation("a")
+	p.Layouts = append(p.Layouts, &slides.Page{
+		ObjectId:         "layout-style",
+		LayoutProperties: &slides.LayoutProperties{Name: "CUSTOM", DisplayName: "style"},
+		PageElements: []*slides.PageElement{
+			{Shape: &slides.Shape{Text: &slides.TextContent{TextElements: []*slides.TextElement{
+				{TextRun: &slides.TextRun{Content: "red\n", Style: &slides.TextStyle{}}},
+			}}}},
+			{Shape: &slides.Shape{Text: &slides.TextContent{TextElements: []*slides.TextElement{
+				{TextRun: &slides.TextRun{Content: "em\n", Style: &slides.TextStyle{}}},
+			}}}},
+		},
+	})
+	s := &fakeServer{presentation: p}
+	d := newFakeDeck(t, s)
+	if got := d.Layouts(); got != nil {
+		t.Errorf("Layouts() before refresh = %v, want nil", got)
+	}
+	if got := d.SlideCount(); got != 0 {
+		t.Errorf("SlideCount() before refresh = %d, want 0", got)
+	}
+	if err := d.refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.SlideCount(), 1; got != want {
+		t.Errorf("SlideCount() = %d, want %d", got, want)
+	}
+	if got, err := d.SlideLayout(0); err != nil || got != "Title and Content" {
+		t.Errorf("SlideLayout(0) = %q, %v, want %q", got, err, "Title and Content")
+	}
+	if _, err := d.SlideLayout(1); err == nil {
+		t.Error("expected error for out-of-range index but got none")
+	}
+	if got, want := d.Layouts(), []string{"Title Slide", "Title and Content", "style"}; !slices.Equal(got, want) {
+		t.Errorf("Layouts() = %v, want %v", got, want)
+	}
+	if got, want := d.StyleNames(), []string{"em", "red"}; !slices.Equal(got, want) {
+		t.Errorf("StyleNames() = %v, want %v", got, want)
+	}
+}
+
+func TestUnusedStyles(t *testing.T) {
+	d := &Deck{styles: map[string]*slides.TextStyle{
+		"bold":       {},
+		"blockquote": {},
+		"cell":       {},
+		"link":       {},
+		"red":        {},
+		"unused":     {},
+	}}
+	ss := Slides{
+		{
+			Bodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{
+				{Value: "a", Bold: true},
+				{Value: "b", StyleName: "red"},
+			}}}}},
+		},
+		{
+			BlockQuotes: []*BlockQuote{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "c"}}}}}},
+			Tables: []*Table{{Rows: []*TableRow{{Cells: []*TableCell{
+				{Fragments: []*Fragment{{Value: "d", StyleName: "cell"}}},
+			}}}}},
+		},
+	}
+	if got, want := d.UnusedStyles(ss), []string{"link", "unused"}; !slices.Equal(got, want) {
+		t.Errorf("UnusedStyles() = %v, want %v", got, want)
+	}
+}
+
+func TestDeleteOrTrashFileWithTrashInsteadOfDelete(t *testing.T) {
+	for _, trash := range []bool{false, true} {
+		var method string
+		s := &fakeServer{
+			presentation: newFakePresentation("a"),
+			driveHandler: func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					_ = json.NewEncoder(w).Encode(&drive.File{Capabilities: &drive.FileCapabilities{CanDelete: true, CanTrash: true}})
+				case http.MethodDelete:
+					method = r.Method
+					w.WriteHeader(http.StatusNoContent)
+				case http.MethodPatch:
+					method = r.Method
+					_ = json.NewEncoder(w).Encode(&drive.File{Id: "file", Trashed: true})
+				}
+			},
+		}
+		d := newFakeDeck(t, s, WithTrashInsteadOfDelete(trash))
+		if err := d.deleteOrTrashFile(t.Context(), "file"); err != nil {
+			t.Fatal(err)
+		}
+		want := http.MethodDelete
+		if trash {
+			want = http.MethodPatch
+		}
+		if method != want {
+			t.Errorf("trash %t: got %s request, want %s", trash, method, want)
+		}
+	}
+}
+
+func TestShareWith(t *testing.T) {
+	var (
+		got    drive.Permission
+		notify string
+	)
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/drive/v3/files/fake-presentation/permissions" {
+				http.NotFound(w, r)
+				return
+			}
+			notify = r.URL.Query().Get("sendNotificationEmail")
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_ = json.NewEncoder(w).Encode(&drive.Permission{Id: "permission"})
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	if err := d.ShareWith(t.Context(), "reviewer@example.com", "commenter", true); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "user" || got.Role != "commenter" || got.EmailAddress != "reviewer@example.com" || notify != "true" {
+		t.Errorf("got %+v (notify %s), want commenter permission for reviewer@example.com with notification", got, notify)
+	}
+	if err := d.ShareWithGroup(t.Context(), "team@example.com", "writer", false); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "group" || got.Role != "writer" || notify != "false" {
+		t.Errorf("got %+v (notify %s), want writer permission for group without notification", got, notify)
+	}
+	if err := d.ShareWith(t.Context(), "reviewer@example.com", "owner", false); err == nil {
+		t.Error("expected error for invalid role but got none")
+	}
+}
+
+func TestResolveFolderID(t *testing.T) {
+	folders := map[string][]*drive.File{
+		"Decks":        {{Id: "folder-1"}},
+		"Bob's decks":  {{Id: "folder-2"}},
+		"Shared decks": {{Id: "folder-3"}, {Id: "folder-4"}},
+	}
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query().Get("q")
+			list := &drive.FileList{}
+			for name, files := range folders {
+				escaped := strings.ReplaceAll(name, "'", `\'`)
+				if strings.Contains(q, "name = '"+escaped+"'") && strings.Contains(q, mimeTypeFolder) {
+					list.Files = files
+				}
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"Decks", "folder-1", false},
+		{"Bob's decks", "folder-2", false},
+		{"Shared decks", "", true},
+		{"Missing", "", true},
+	}
+	for _, tt := range tests {
+		got, err := d.resolveFolderID(t.Context(), tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := newDeckWithOptions(WithFolderName(" ")); err == nil {
+		t.Error("expected error for blank folder name but got none")
+	}
+}
+
+func TestPreparePagesOrder(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("first", "last")}
+	// Apply the requests in order, as the Slides API does.
+	s.batchUpdateHook = func(req *slides.BatchUpdatePresentationRequest) error {
+		for _, r := range req.Requests {
+			c := r.CreateSlide
+			s.presentation.Slides = slices.Insert(s.presentation.Slides, int(c.InsertionIndex), &slides.Page{
+				ObjectId:        c.ObjectId,
+				SlideProperties: &slides.SlideProperties{LayoutObjectId: c.SlideLayoutReference.LayoutId},
+			})
+		}
+		return nil
+	}
+	d := newFakeDeck(t, s)
+	if err := d.refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	var layoutIDs []string
+	for i := range 50 {
+		layoutIDs = append(layoutIDs, []string{"layout-title", "layout-body"}[i%3%2])
+	}
+	if err := d.preparePages(t.Context(), 1, layoutIDs); err != nil {
+		t.Fatal(err)
+	}
+	got := d.presentation.Slides
+	if len(got) != len(layoutIDs)+2 {
+		t.Fatalf("got %d slides, want %d", len(got), len(layoutIDs)+2)
+	}
+	if got[0].ObjectId != "slide-0" || got[len(got)-1].ObjectId != "slide-1" {
+		t.Error("existing slides should stay before and after the created slides")
+	}
+	for i, layoutID := range layoutIDs {
+		if l := got[i+1].SlideProperties.LayoutObjectId; l != layoutID {
+			t.Errorf("slide %d: got layout %s, want %s", i+1, l, layoutID)
+		}
+	}
+}
+
+func TestRevokePublicAccess(t *testing.T) {
+	var deleted []string
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			const path = "/drive/v3/files/fake-presentation/permissions"
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == path:
+				if r.URL.Query().Get("pageToken") == "" {
+					_ = json.NewEncoder(w).Encode(&drive.PermissionList{
+						NextPageToken: "next",
+						Permissions: []*drive.Permission{
+							{Id: "owner", Type: "user", Role: "owner", EmailAddress: "owner@example.com"},
+							{Id: "anyoneWithLink", Type: "anyone", Role: "reader"},
+						},
+					})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(&drive.PermissionList{
+					Permissions: []*drive.Permission{{Id: "domain", Type: "domain", Role: "commenter", Domain: "example.com"}},
+				})
+			case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, path+"/"):
+				deleted = append(deleted, strings.TrimPrefix(r.URL.Path, path+"/"))
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	got, err := d.Permissions(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Permission{
+		{ID: "owner", Type: "user", Role: "owner", EmailAddress: "owner@example.com"},
+		{ID: "anyoneWithLink", Type: "anyone", Role: "reader"},
+		{ID: "domain", Type: "domain", Role: "commenter", Domain: "example.com"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if err := d.RevokePublicAccess(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(deleted, []string{"anyoneWithLink"}) {
+		t.Errorf("got deleted %v, want [anyoneWithLink]", deleted)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("Slide 1", "Slide 2")}
+	d := newFakeDeck(t, s)
+	ss := Slides{
+		{Layout: "Title and Content", Titles: []string{"Slide 1"}},
+		{Layout: "Title and Content", Titles: []string{"Slide 2"}},
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := d.Apply(t.Context(), ss); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := d.AllSpeakerNotes(t.Context()); err != nil {
+				t.Error(err)
+			}
+			_ = d.SlideCount()
+			_ = d.Layouts()
+			_ = Lint(ss, d)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentSetFolder(t *testing.T) {
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			switch id := strings.TrimPrefix(r.URL.Path, "/drive/v3/files/"); id {
+			case "/drive/v3/files":
+				_ = json.NewEncoder(w).Encode(&drive.FileList{})
+			case "fake-presentation":
+				_ = json.NewEncoder(w).Encode(&drive.File{Id: id, MimeType: "application/vnd.google-apps.presentation"})
+			default:
+				_ = json.NewEncoder(w).Encode(&drive.File{Id: id, MimeType: "application/vnd.google-apps.folder"})
+			}
+		},
+	}
+	d := newFakeDeck(t, s)
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := d.SetFolder(t.Context(), fmt.Sprintf("folder-%d", i)); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				if _, err := d.ListPresentations(t.Context()); err != nil {
+					t.Error(err)
+				}
+				if _, err := d.CleanupOrphanedImages(t.Context(), time.Hour); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,11 @@
+package deck
+
+import "strings"
+
+// editedMarker is the marker in the speaker notes of a slide that has been edited manually.
+const editedMarker = "[deck:edited]"
+
+// isEditedSlide reports whether the slide is marked as edited manually in its speaker notes.
+func isEditedSlide(slide *Slide) bool {
+	return slide != nil && strings.Contains(slide.SpeakerNote, editedMarker)
+}
@@ -3,9 +3,13 @@ package deck
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
 
 	"github.com/k1LoW/errors"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/slides/v1"
 )
 
@@ -16,6 +20,8 @@ func (d *Deck) AppendPage(ctx context.Context, slide *Slide) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	d.logger.Info("appending new page")
 	index := len(d.presentation.Slides)
 	if err := d.createPage(ctx, index, slide); err != nil {
@@ -55,17 +61,23 @@ func Delete(ctx context.Context, id string, opts ...Option) (err error) {
 	return nil
 }
 
-// InsertPage inserts a new slide at the specified index in the presentation.
-// The deck command currently does not utilize this method and is only used within tests;
-// however, it has been retained for potential future usage as a library.
+// InsertPage inserts a new slide at the specified index in the presentation and populates its content.
+// Out-of-range indices are clamped to the start or the end of the presentation.
+// If the slide has no layout, the default layout for the index is used.
+// It returns a LayoutNotFoundError without creating the slide if the presentation does not have the layout.
 func (d *Deck) InsertPage(ctx context.Context, index int, slide *Slide) (err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
-	d.logger.Info("inserting page", slog.Int("index", index))
-	if len(d.presentation.Slides) <= index {
-		return fmt.Errorf("index out of range: %d", index)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	index = max(0, min(index, len(d.presentation.Slides)))
+	slide.Layout = d.layoutOrDefault(index, slide.Layout)
+	layoutMap := d.layoutMap()
+	if _, ok := layoutMap[slide.Layout]; !ok {
+		return newLayoutNotFoundError([]string{slide.Layout}, layoutMap)
 	}
+	d.logger.Info("inserting page", slog.Int("index", index))
 	if err := d.createPage(ctx, index, slide); err != nil {
 		return fmt.Errorf("failed to create page: %w", err)
 	}
@@ -98,6 +110,8 @@ func (d *Deck) DumpSlides(ctx context.Context) (_ Slides, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if err := d.refresh(ctx); err != nil {
 		return nil, fmt.Errorf("failed to refresh presentation: %w", err)
 	}
@@ -115,3 +129,117 @@ func (d *Deck) DumpSlides(ctx context.Context) (_ Slides, err error) {
 	}
 	return slides, nil
 }
+
+// DumpMarkdown retrieves all slides from the presentation and renders them as markdown.
+func (d *Deck) DumpMarkdown(ctx context.Context) (_ string, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	ss, err := d.DumpSlides(ctx)
+	if err != nil {
+		return "", err
+	}
+	return ss.Markdown(), nil
+}
+
+// ExportJob is a presentation to export as PDF by ExportAll.
+// The PDF is written to Writer if set, otherwise to the file at Path.
+type ExportJob struct {
+	PresentationID string
+	Writer         io.Writer
+	Path           string
+}
+
+// ExportAll exports multiple presentations to PDF concurrently.
+// All jobs share one authenticated Drive service, and failed API requests are retried by its HTTP client.
+// At most concurrency jobs run at the same time. If concurrency is less than 1, the default of 4 is used.
+// The returned errors correspond to jobs by index, and are nil for jobs that succeeded.
+// Options such as WithProfile are used to authenticate; WithPresentationID is ignored.
+func ExportAll(ctx context.Context, jobs []ExportJob, concurrency int, opts ...Option) []error {
+	d, err := newDeck(ctx, opts...)
+	if err != nil {
+		errs := make([]error, len(jobs))
+		for i := range errs {
+			errs[i] = errors.WithStack(err)
+		}
+		return errs
+	}
+	return d.exportAll(ctx, jobs, concurrency)
+}
+
+func (d *Deck) exportAll(ctx context.Context, jobs []ExportJob, concurrency int) []error {
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	errs := make([]error, len(jobs))
+	eg := errgroup.Group{}
+	eg.SetLimit(concurrency)
+	for i, job := range jobs {
+		eg.Go(func() error {
+			if err := d.exportJob(ctx, job); err != nil {
+				errs[i] = fmt.Errorf("failed to export presentation %s: %w", job.PresentationID, err)
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return errs
+}
+
+func (d *Deck) exportJob(ctx context.Context, job ExportJob) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	if job.PresentationID == "" {
+		return fmt.Errorf("presentation ID is required")
+	}
+	if job.Writer != nil {
+		return d.exportPDF(ctx, job.PresentationID, job.Writer)
+	}
+	if job.Path == "" {
+		return fmt.Errorf("either writer or path is required")
+	}
+	return d.exportPDFToFile(ctx, job.PresentationID, job.Path)
+}
+
+// exportPDF writes the presentation as PDF to w.
+// The PDF is streamed from the response with a fixed-size buffer, so memory use does not grow with its size.
+// The request timeout covers both the request and the download of the PDF.
+func (d *Deck) exportPDF(ctx context.Context, presentationID string, w io.Writer) error {
+	return d.call(ctx, "export", func(ctx context.Context) error {
+		res, err := d.driveSrv.Files.Export(presentationID, "application/pdf").Context(ctx).Download()
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if _, err := io.Copy(w, res.Body); err != nil {
+			return fmt.Errorf("unable to create PDF file: %w", err)
+		}
+		return nil
+	})
+}
+
+// exportPDFToFile writes the presentation as PDF to a temporary file, and renames it to path on success.
+func (d *Deck) exportPDFToFile(ctx context.Context, presentationID, path string) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+	if err := d.exportPDF(ctx, presentationID, f); err != nil {
+		return err
+	}
+	// os.CreateTemp creates the file with 0600, so make it as readable as a file created by os.Create.
+	if err := f.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
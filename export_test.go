@@ -0,0 +1,223 @@
+package deck
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestExportAll(t *testing.T) {
+	s := &fakeServer{
+		presentation: newFakePresentation(),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/drive/v3/files/"), "/export")
+			if !ok || id == "missing" || r.URL.Query().Get("mimeType") != "application/pdf" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte("%PDF-" + id))
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	dir := t.TempDir()
+	bufs := make([]*bytes.Buffer, 3)
+	jobs := []ExportJob{}
+	for i, id := range []string{"p0", "p1", "p2"} {
+		bufs[i] = &bytes.Buffer{}
+		jobs = append(jobs, ExportJob{PresentationID: id, Writer: bufs[i]})
+	}
+	jobs = append(jobs,
+		ExportJob{PresentationID: "p3", Path: filepath.Join(dir, "p3.pdf")},
+		ExportJob{PresentationID: "missing", Path: filepath.Join(dir, "missing.pdf")},
+		ExportJob{PresentationID: "p5"},
+	)
+
+	errs := d.exportAll(t.Context(), jobs, 2)
+	if len(errs) != len(jobs) {
+		t.Fatalf("got %d errors, want %d", len(errs), len(jobs))
+	}
+	for i, buf := range bufs {
+		if errs[i] != nil {
+			t.Errorf("job %d: unexpected error: %v", i, errs[i])
+		}
+		if want := "%PDF-" + jobs[i].PresentationID; buf.String() != want {
+			t.Errorf("job %d: got %q, want %q", i, buf.String(), want)
+		}
+	}
+	if errs[3] != nil {
+		t.Errorf("job 3: unexpected error: %v", errs[3])
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "p3.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "%PDF-p3" {
+		t.Errorf("got %q, want %q", b, "%PDF-p3")
+	}
+	if errs[4] == nil {
+		t.Error("job 4: expected error but got none")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.pdf")); !os.IsNotExist(err) {
+		t.Error("file of failed export should be removed")
+	}
+	if errs[5] == nil {
+		t.Error("job 5: expected error but got none")
+	}
+}
+
+func TestExportTo(t *testing.T) {
+	const size = 64 << 20 // as large as the PDF of hundreds of slides with images
+	fail := false
+	s := &fakeServer{
+		presentation: newFakePresentation(),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			if fail {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte("%PDF-"))
+			chunk := make([]byte, 1<<20)
+			for range size >> 20 {
+				_, _ = w.Write(chunk)
+			}
+		},
+	}
+	d := newFakeDeck(t, s)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deck.pdf")
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if err := d.ExportTo(t.Context(), path); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	// The PDF is streamed to the file, so the allocations are far less than its size.
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > size/4 {
+		t.Errorf("allocated %d bytes to export %d bytes", alloc, size)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != size+int64(len("%PDF-")) {
+		t.Errorf("got %d bytes, want %d", fi.Size(), size+len("%PDF-"))
+	}
+
+	fail = true
+	if err := d.ExportTo(t.Context(), path); err == nil {
+		t.Fatal("expected error but got none")
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() != size+int64(len("%PDF-")) {
+		t.Error("failed export should leave the existing file as is")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d files, the temporary file of the failed export should be removed", len(entries))
+	}
+}
+
+func TestInsertPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		index     int
+		wantIndex int64
+	}{
+		{"middle", 1, 1},
+		{"negative", -1, 0},
+		{"after the end", 99, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s *fakeServer
+			s = &fakeServer{
+				presentation: newFakePresentation("Slide 1", "Slide 2"),
+				batchUpdateHook: func(req *slides.BatchUpdatePresentationRequest) error {
+					for _, r := range req.Requests {
+						if r.CreateSlide == nil {
+							continue
+						}
+						p := newFakePresentation("New")
+						p.Slides[0].ObjectId = fmt.Sprintf("new-%d", len(s.presentation.Slides))
+						s.presentation.Slides = slices.Insert(s.presentation.Slides, int(r.CreateSlide.InsertionIndex), p.Slides[0])
+					}
+					return nil
+				},
+			}
+			d := newFakeDeck(t, s)
+			if err := d.refresh(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.InsertPage(t.Context(), tt.index, &Slide{Layout: "Title and Content"}); err != nil {
+				t.Fatal(err)
+			}
+			create := s.batchUpdates[0].Requests[0].CreateSlide
+			if create == nil {
+				t.Fatalf("first request is not CreateSlide: %+v", s.batchUpdates[0].Requests[0])
+			}
+			if create.InsertionIndex != tt.wantIndex {
+				t.Errorf("got insertion index %d, want %d", create.InsertionIndex, tt.wantIndex)
+			}
+			if create.SlideLayoutReference.LayoutId != "layout-body" {
+				t.Errorf("got layout %q, want %q", create.SlideLayoutReference.LayoutId, "layout-body")
+			}
+		})
+	}
+
+	t.Run("unknown layout", func(t *testing.T) {
+		s := &fakeServer{presentation: newFakePresentation("Slide 1")}
+		d := newFakeDeck(t, s)
+		if err := d.refresh(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		err := d.InsertPage(t.Context(), 0, &Slide{Layout: "Missing"})
+		var lerr *LayoutNotFoundError
+		if !errors.As(err, &lerr) {
+			t.Fatalf("got %v, want LayoutNotFoundError", err)
+		}
+		if len(s.batchUpdates) != 0 {
+			t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+		}
+	})
+}
+
+func TestDumpMarkdown(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a", "b")}
+	body := &slides.PageElement{
+		ObjectId:  "slide-1-body",
+		Transform: &slides.AffineTransform{},
+		Shape: &slides.Shape{
+			Placeholder: &slides.Placeholder{Type: "BODY"},
+			Text: &slides.TextContent{TextElements: []*slides.TextElement{
+				{ParagraphMarker: &slides.ParagraphMarker{}},
+				{TextRun: &slides.TextRun{Content: "go", Style: &slides.TextStyle{Link: &slides.Link{Url: "https://go.dev/"}}}},
+				{TextRun: &slides.TextRun{Content: "\n"}},
+			}},
+		},
+	}
+	s.presentation.Slides[1].PageElements = append(s.presentation.Slides[1].PageElements, body)
+	d := newFakeDeck(t, s)
+
+	got, err := d.DumpMarkdown(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# a\n\n---\n\n# b\n\n[go](https://go.dev/)\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/k1LoW/errors"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// ExtractImages downloads every image in the presentation and uploads it to the storage in parallel.
+// It returns a map of the object ID of the image to the public URL of the uploaded image.
+// Uploaded images are not deleted, so that they can be used from elsewhere.
+func (d *Deck) ExtractImages(ctx context.Context, storage Storage) (_ map[string]string, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.refresh(ctx); err != nil {
+		return nil, err
+	}
+	contentURLs := map[string]string{} // key: objectID, value: content URL
+	for _, s := range d.presentation.Slides {
+		for _, element := range s.PageElements {
+			if element.Image != nil && element.Image.Placeholder == nil && element.Image.ContentUrl != "" {
+				contentURLs[element.ObjectId] = element.Image.ContentUrl
+			}
+		}
+	}
+	extracted := make(map[string]string, len(contentURLs))
+	if len(contentURLs) == 0 {
+		return extracted, nil
+	}
+	d.logger.Info("extracting images", slog.Int("count", len(contentURLs)))
+
+	var mu sync.Mutex
+	sem := semaphore.NewWeighted(int64(d.workersNum()))
+	eg, ctx := errgroup.WithContext(ctx)
+	for objectID, contentURL := range contentURLs {
+		eg.Go(func() error {
+			if err := sem.Acquire(ctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			image, err := d.fetchImage(contentURL, false)
+			if err != nil {
+				return fmt.Errorf("failed to download image %s: %w", objectID, err)
+			}
+			publicURL, _, err := storage.Upload(ctx, image.Bytes(), string(image.mimeType))
+			if err != nil {
+				return fmt.Errorf("failed to upload image %s: %w", objectID, err)
+			}
+			mu.Lock()
+			extracted[objectID] = publicURL
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	d.logger.Info("extracted images", slog.Int("count", len(extracted)))
+	return extracted, nil
+}
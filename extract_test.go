@@ -0,0 +1,44 @@
+package deck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestExtractImages(t *testing.T) {
+	img := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(img.Close)
+
+	p := newFakePresentation("a", "b")
+	p.Slides[0].PageElements = append(p.Slides[0].PageElements, &slides.PageElement{
+		ObjectId: "image-png",
+		Image:    &slides.Image{ContentUrl: img.URL + "/test.png"},
+	})
+	p.Slides[1].PageElements = append(p.Slides[1].PageElements, &slides.PageElement{
+		ObjectId: "image-jpeg",
+		Image:    &slides.Image{ContentUrl: img.URL + "/test.jpeg"},
+	}, &slides.PageElement{
+		ObjectId: "image-placeholder",
+		Image:    &slides.Image{Placeholder: &slides.Placeholder{Type: "PICTURE"}},
+	})
+	s := &fakeServer{presentation: p}
+	d := newFakeDeck(t, s)
+	storage := &countingStorage{}
+
+	got, err := d.ExtractImages(t.Context(), storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["image-png"] == "" || got["image-jpeg"] == "" {
+		t.Errorf("got %v, want public URLs of the two images", got)
+	}
+	if storage.uploads != 2 {
+		t.Errorf("got %d uploads, want 2", storage.uploads)
+	}
+	if len(storage.deletes) != 0 {
+		t.Errorf("extracted images should not be deleted: %v", storage.deletes)
+	}
+}
@@ -0,0 +1,96 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/k1LoW/errors"
+)
+
+// FallbackStorage implements Storage by trying each of the storages in order until one of them uploads the image.
+// Uploaded images are deleted from the storage that uploaded them.
+type FallbackStorage struct {
+	storages []Storage
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	uploaded map[string]int // uploaded ID -> index of the storage that uploaded it
+}
+
+var _ Storage = (*FallbackStorage)(nil)
+
+// NewFallbackStorage creates a new FallbackStorage trying storages in the given order.
+func NewFallbackStorage(storages ...Storage) *FallbackStorage {
+	return &FallbackStorage{
+		storages: storages,
+		logger:   slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		uploaded: map[string]int{},
+	}
+}
+
+// Upload uploads the image to the first storage that succeeds.
+// It returns the errors of all the storages if none of them succeeds.
+func (s *FallbackStorage) Upload(ctx context.Context, data []byte, mimeType string) (publicURL, uploadedID string, err error) {
+	s.mu.Lock()
+	storages := slices.Clone(s.storages)
+	s.mu.Unlock()
+	var errs []error
+	for i, storage := range storages {
+		publicURL, uploadedID, err := storage.Upload(ctx, data, mimeType)
+		if err != nil {
+			s.logger.Debug("failed to upload image, trying next storage", slog.Int("storage", i), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("storage %d: %w", i, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		s.mu.Lock()
+		s.uploaded[uploadedID] = i
+		s.mu.Unlock()
+		s.logger.Debug("uploaded image", slog.Int("storage", i), slog.String("id", uploadedID))
+		return publicURL, uploadedID, nil
+	}
+	return "", "", fmt.Errorf("failed to upload image to any storage: %w", errors.Join(errs...))
+}
+
+// Delete deletes the image from the storage that uploaded it.
+func (s *FallbackStorage) Delete(ctx context.Context, uploadedID string) error {
+	s.mu.Lock()
+	i, ok := s.uploaded[uploadedID]
+	delete(s.uploaded, uploadedID)
+	var storage Storage
+	if ok {
+		storage = s.storages[i]
+	}
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown uploaded image: %s", uploadedID)
+	}
+	return storage.Delete(ctx, uploadedID)
+}
+
+// setLast replaces the last of the storages, keeping track of the images uploaded so far.
+// An image uploaded by the replaced storage is deleted by the new one.
+func (s *FallbackStorage) setLast(storage Storage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storages[len(s.storages)-1] = storage
+}
+
+// Close closes the storages that implement io.Closer.
+func (s *FallbackStorage) Close() error {
+	var errs []error
+	for _, storage := range s.storages {
+		if c, ok := storage.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
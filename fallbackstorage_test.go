@@ -0,0 +1,93 @@
+package deck
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type failingStorage struct {
+	uploads int
+}
+
+func (s *failingStorage) Upload(ctx context.Context, data []byte, mimeType string) (string, string, error) {
+	s.uploads++
+	return "", "", errors.New("unavailable")
+}
+
+func (s *failingStorage) Delete(ctx context.Context, uploadedID string) error {
+	return errors.New("unavailable")
+}
+
+func TestFallbackStorage(t *testing.T) {
+	primary := &failingStorage{}
+	secondary := &countingStorage{}
+	s := NewFallbackStorage(primary, secondary)
+
+	publicURL, uploadedID, err := s.Upload(t.Context(), []byte("image"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicURL != "https://example.com/uploaded-1" || uploadedID != "uploaded-1" {
+		t.Errorf("got %s %s, want the upload of the secondary storage", publicURL, uploadedID)
+	}
+	if primary.uploads != 1 || secondary.uploads != 1 {
+		t.Errorf("got uploads %d/%d, want 1/1", primary.uploads, secondary.uploads)
+	}
+	if err := s.Delete(t.Context(), uploadedID); err != nil {
+		t.Fatal(err)
+	}
+	if secondary.deletes[uploadedID] != 1 {
+		t.Errorf("image was not deleted from the secondary storage: %v", secondary.deletes)
+	}
+	if err := s.Delete(t.Context(), uploadedID); err == nil {
+		t.Error("expected error for deleting an unknown image but got none")
+	}
+
+	if _, _, err := NewFallbackStorage(primary).Upload(t.Context(), []byte("image"), "image/png"); err == nil {
+		t.Error("expected error when all storages fail but got none")
+	}
+}
+
+func TestWithStorageChain(t *testing.T) {
+	if _, err := newDeckWithOptions(WithStorageChain(nil)); err == nil {
+		t.Error("expected error for nil storage but got none")
+	}
+	d, err := newDeckWithOptions(WithStorageChain(&countingStorage{}), WithImageUploadCmd("upload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := d.getStorage().(*FallbackStorage)
+	if !ok {
+		t.Fatalf("got %T, want *FallbackStorage", d.getStorage())
+	}
+	if len(s.storages) != 2 {
+		t.Fatalf("got %d storages, want 2", len(s.storages))
+	}
+	if _, ok := s.storages[1].(*externalStorage); !ok {
+		t.Errorf("got %T as the last storage, want the image upload command", s.storages[1])
+	}
+
+	// Images uploaded through the storage can be deleted through the storage got later.
+	primary := &closingStorage{}
+	d, err = newDeckWithOptions(WithStorageChain(primary), WithImageUploadCmd("upload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, uploadedID, err := d.getStorage().Upload(t.Context(), []byte("image"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.getStorage().Delete(t.Context(), uploadedID); err != nil {
+		t.Fatal(err)
+	}
+	if primary.deletes[uploadedID] != 1 {
+		t.Errorf("image was not deleted: %v", primary.deletes)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if primary.closed != 1 {
+		t.Errorf("got %d closes, want 1", primary.closed)
+	}
+}
@@ -0,0 +1,54 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"slices"
+
+	"github.com/k1LoW/errors"
+)
+
+// HEIC/HEIF images (e.g., photos and screenshots from iPhones) are not accepted by the Slides API,
+// so they are transcoded to JPEG. There is no HEVC decoder in pure Go, and decoders such as
+// github.com/jdeng/goheif require cgo, so deck does not depend on one. A program that embeds deck
+// can enable the conversion by registering a decoder with image.RegisterFormat, typically by
+// importing it for side effects:
+//
+//	import _ "github.com/jdeng/goheif"
+//
+// Without a registered decoder, HEIC/HEIF images fail with ErrHEICNotSupported.
+
+// ErrHEICNotSupported is returned for HEIC/HEIF images when no decoder for them is registered.
+var ErrHEICNotSupported = errors.New("HEIC/HEIF images are not supported in this build: convert the image to JPEG or PNG, or register a HEIC decoder with image.RegisterFormat")
+
+// heicBrands are the major brands of the ftyp box of HEIC/HEIF images.
+var heicBrands = []string{"heic", "heix", "hevc", "hevx", "heim", "heis", "hevm", "hevs", "mif1", "msf1"}
+
+// heicJPEGQuality is the quality of JPEG images transcoded from HEIC/HEIF images.
+const heicJPEGQuality = 90
+
+// isHEIC reports whether b is a HEIC/HEIF image.
+func isHEIC(b []byte) bool {
+	if len(b) < 12 || string(b[4:8]) != "ftyp" {
+		return false
+	}
+	return slices.Contains(heicBrands, string(b[8:12]))
+}
+
+// transcodeHEIC transcodes the HEIC/HEIF image to JPEG with the decoder registered with image.RegisterFormat.
+func transcodeHEIC(b []byte) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, ErrHEICNotSupported
+		}
+		return nil, fmt.Errorf("failed to decode heic image: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: heicJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("failed to transcode heic image to jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
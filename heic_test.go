@@ -0,0 +1,69 @@
+package deck
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+
+	"github.com/k1LoW/errors"
+)
+
+func heicHeader(brand string) []byte {
+	return append([]byte("\x00\x00\x00\x18ftyp"+brand), []byte("\x00\x00\x00\x00mif1heic")...)
+}
+
+func TestNewImageTranscodesHEIC(t *testing.T) {
+	// A fake decoder stands in for a real HEIC decoder registered by the program.
+	image.RegisterFormat("heic-fake", "????ftypheix",
+		func(r io.Reader) (image.Image, error) {
+			img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+			img.Set(0, 0, color.RGBA{R: 255, A: 255})
+			return img, nil
+		},
+		func(r io.Reader) (image.Config, error) {
+			return image.Config{ColorModel: color.RGBAModel, Width: 4, Height: 3}, nil
+		})
+
+	t.Run("transcoded to jpeg", func(t *testing.T) {
+		i, err := newImageFromBuffer(bytes.NewReader(heicHeader("heix")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.mimeType != MIMETypeImageJPEG || !i.transcoded {
+			t.Errorf("got mimeType %s and transcoded %t, want %s and true", i.mimeType, i.transcoded, MIMETypeImageJPEG)
+		}
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(i.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != "jpeg" || cfg.Width != 4 || cfg.Height != 3 {
+			t.Errorf("got %s %dx%d, want jpeg 4x3", format, cfg.Width, cfg.Height)
+		}
+	})
+
+	t.Run("no decoder", func(t *testing.T) {
+		_, err := newImageFromBuffer(bytes.NewReader(heicHeader("heic")))
+		if !errors.Is(err, ErrHEICNotSupported) {
+			t.Errorf("got %v, want %v", err, ErrHEICNotSupported)
+		}
+	})
+}
+
+func TestIsHEIC(t *testing.T) {
+	tests := []struct {
+		b    []byte
+		want bool
+	}{
+		{heicHeader("heic"), true},
+		{heicHeader("mif1"), true},
+		{heicHeader("isom"), false},
+		{[]byte("\x89PNG\r\n\x1a\n"), false},
+	}
+	for _, tt := range tests {
+		if got := isHEIC(tt.b); got != tt.want {
+			t.Errorf("isHEIC(%q) = %t, want %t", tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,11 +2,23 @@ package deck
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/chromedp/chromedp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/slides/v1"
 )
 
 const (
@@ -55,3 +67,136 @@ func Screenshot(t *testing.T, url string) []byte {
 
 	return buf
 }
+
+// fakeServer is a fake Google Slides/Drive API server for testing.
+type fakeServer struct {
+	mu           sync.Mutex
+	presentation *slides.Presentation
+	batchUpdates []*slides.BatchUpdatePresentationRequest
+	driveHandler http.HandlerFunc
+	// batchUpdateHook is called with each batch update request. If it returns an error, the server responds with it.
+	batchUpdateHook func(req *slides.BatchUpdatePresentationRequest) error
+	// batchUpdateReplies returns the replies to each batch update request, if set.
+	batchUpdateReplies func(req *slides.BatchUpdatePresentationRequest) []*slides.Response
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/drive/"), strings.HasPrefix(r.URL.Path, "/upload/drive/"):
+		if s.driveHandler == nil {
+			http.NotFound(w, r)
+			return
+		}
+		s.driveHandler(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, ":batchUpdate"):
+		req := &slides.BatchUpdatePresentationRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.batchUpdates = append(s.batchUpdates, req)
+		if s.batchUpdateHook != nil {
+			if err := s.batchUpdateHook(req); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		res := &slides.BatchUpdatePresentationResponse{
+			PresentationId: s.presentation.PresentationId,
+		}
+		if s.batchUpdateReplies != nil {
+			res.Replies = s.batchUpdateReplies(req)
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/thumbnail"):
+		pageID := path.Base(path.Dir(r.URL.Path))
+		_ = json.NewEncoder(w).Encode(&slides.Thumbnail{
+			ContentUrl: "https://example.com/thumbnails/" + pageID,
+			Width:      1600,
+			Height:     900,
+		})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/presentations/"):
+		_ = json.NewEncoder(w).Encode(s.presentation)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newFakeDeck creates a Deck backed by a fakeServer serving the presentation.
+func newFakeDeck(t *testing.T, s *fakeServer, opts ...Option) *Deck {
+	t.Helper()
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	d := &Deck{
+		id:         s.presentation.PresentationId,
+		styles:     map[string]*slides.TextStyle{},
+		shapes:     map[string]*slides.ShapeProperties{},
+		tableStyle: defaultTableStyle(),
+		logger:     slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	}
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := &http.Client{Transport: d.transport(http.DefaultTransport)}
+	srv, err := slides.NewService(t.Context(), option.WithHTTPClient(client), option.WithEndpoint(ts.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.srv = srv
+	driveSrv, err := drive.NewService(t.Context(), option.WithHTTPClient(client), option.WithEndpoint(ts.URL+"/drive/v3/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.driveSrv = driveSrv
+	return d
+}
+
+// newFakePresentation returns a presentation with a title layout, a title and body layout,
+// and a slide for each of the titles.
+func newFakePresentation(titles ...string) *slides.Presentation {
+	p := &slides.Presentation{
+		PresentationId: "fake-presentation",
+		Layouts: []*slides.Page{{
+			ObjectId:         "layout-title",
+			LayoutProperties: &slides.LayoutProperties{Name: "TITLE", DisplayName: "Title Slide"},
+		}, {
+			ObjectId:         "layout-body",
+			LayoutProperties: &slides.LayoutProperties{Name: "TITLE_AND_BODY", DisplayName: "Title and Content"},
+		}},
+	}
+	for i, title := range titles {
+		p.Slides = append(p.Slides, &slides.Page{
+			ObjectId: fmt.Sprintf("slide-%d", i),
+			PageElements: []*slides.PageElement{{
+				ObjectId:  fmt.Sprintf("slide-%d-title", i),
+				Transform: &slides.AffineTransform{},
+				Shape: &slides.Shape{
+					Placeholder: &slides.Placeholder{Type: "TITLE"},
+					Text: &slides.TextContent{
+						TextElements: []*slides.TextElement{{
+							ParagraphMarker: &slides.ParagraphMarker{},
+						}, {
+							TextRun: &slides.TextRun{Content: title + "\n"},
+						}},
+					},
+				},
+			}},
+			SlideProperties: &slides.SlideProperties{
+				LayoutObjectId: "layout-body",
+				NotesPage: &slides.Page{
+					PageElements: []*slides.PageElement{{
+						ObjectId: fmt.Sprintf("slide-%d-notes", i),
+						Shape: &slides.Shape{
+							Placeholder: &slides.Placeholder{Type: "BODY"},
+						},
+					}},
+				},
+			},
+		})
+	}
+	return p
+}
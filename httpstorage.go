@@ -0,0 +1,110 @@
+package deck
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k1LoW/errors"
+)
+
+// HTTPServerStorage implements Storage by serving uploaded images from an embedded HTTP server.
+// It is intended for setups where no cloud storage is available.
+// The base URL must be reachable from Google's servers (e.g., via an ngrok-style tunnel),
+// because the Slides API fetches images from the returned URLs.
+type HTTPServerStorage struct {
+	baseURL string
+	server  *http.Server
+
+	mu     sync.RWMutex
+	images map[string]httpServerImage
+}
+
+type httpServerImage struct {
+	data     []byte
+	mimeType string
+}
+
+var _ Storage = (*HTTPServerStorage)(nil)
+
+// NewHTTPServerStorage creates a new HTTPServerStorage returning URLs like {baseURL}/{id}.
+func NewHTTPServerStorage(baseURL string) *HTTPServerStorage {
+	s := &HTTPServerStorage{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		images:  map[string]httpServerImage{},
+	}
+	s.server = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start starts serving uploaded images on addr (e.g., ":8080") in the background.
+func (s *HTTPServerStorage) Start(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	go func() {
+		_ = s.server.Serve(l)
+	}()
+	return nil
+}
+
+// Close stops the HTTP server.
+func (s *HTTPServerStorage) Close(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Upload registers the image in memory and returns the URL to serve it.
+func (s *HTTPServerStorage) Upload(ctx context.Context, data []byte, mimeType string) (publicURL, uploadedID string, err error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate image ID: %w", err)
+	}
+	uploadedID = hex.EncodeToString(b)
+	s.mu.Lock()
+	s.images[uploadedID] = httpServerImage{data: data, mimeType: mimeType}
+	s.mu.Unlock()
+	return s.baseURL + "/" + uploadedID, uploadedID, nil
+}
+
+// Delete unregisters the image.
+func (s *HTTPServerStorage) Delete(ctx context.Context, uploadedID string) error {
+	s.mu.Lock()
+	delete(s.images, uploadedID)
+	s.mu.Unlock()
+	return nil
+}
+
+// ServeHTTP serves the registered image whose ID is the last element of the request path.
+func (s *HTTPServerStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	s.mu.RLock()
+	img, ok := s.images[id]
+	s.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", img.mimeType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(img.data)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(img.data)
+}
@@ -0,0 +1,54 @@
+package deck
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPServerStorage(t *testing.T) {
+	s := NewHTTPServerStorage("https://example.com/images/")
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	publicURL, id, err := s.Upload(t.Context(), []byte("png data"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://example.com/images/" + id; publicURL != want {
+		t.Errorf("got %q, want %q", publicURL, want)
+	}
+
+	res, err := http.Get(ts.URL + strings.TrimPrefix(publicURL, "https://example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", res.StatusCode, http.StatusOK)
+	}
+	if got := res.Header.Get("Content-Type"); got != "image/png" {
+		t.Errorf("got Content-Type %q, want %q", got, "image/png")
+	}
+	if string(b) != "png data" {
+		t.Errorf("got %q, want %q", b, "png data")
+	}
+
+	if err := s.Delete(t.Context(), id); err != nil {
+		t.Fatal(err)
+	}
+	res, err = http.Get(ts.URL + "/" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("got status %d after delete, want %d", res.StatusCode, http.StatusNotFound)
+	}
+}
@@ -10,7 +10,7 @@ import (
 	"image"
 	_ "image/gif"
 	_ "image/jpeg"
-	_ "image/png"
+	"image/png"
 	"io"
 	"net"
 	"net/http"
@@ -22,7 +22,9 @@ import (
 
 	"github.com/corona10/goimagehash"
 	"github.com/k1LoW/errors"
+	"golang.org/x/image/webp"
 	"golang.org/x/net/publicsuffix"
+	"google.golang.org/api/slides/v1"
 )
 
 type MIMEType string
@@ -43,6 +45,13 @@ type Image struct {
 	pHash        *goimagehash.ImageHash // Perceptual hash for JPEG images
 	modTime      time.Time              // Modification time of the image file, if applicable
 	link         string                 // External link associated with the image
+	transcoded   bool                   // Whether the image data was transcoded from a format the Slides API does not accept
+	svg          []byte                 // Original SVG data if the image was rasterized from SVG
+	slot         string                 // Name of the image placeholder to place the image in
+	alt          string                 // Alternative text of the image
+	width        string                 // Width of the image on the slide, in points or percent of the page width
+	height       string                 // Height of the image on the slide, in points or percent of the page height
+	element      *slides.PageElement    // Page element the image was read from, if any
 
 	// Upload state management
 	uploadMutex    sync.RWMutex
@@ -60,10 +69,19 @@ const (
 	uploadStateFailed
 )
 
+// defaultImageFetchTimeout is the timeout for fetching an image from a URL.
+// It is generous so that a slow external source does not fail an otherwise fast apply.
+const defaultImageFetchTimeout = 60 * time.Second
+
 func NewImage(pathOrURL string) (_ *Image, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	return newImageWithTimeout(pathOrURL, defaultImageFetchTimeout)
+}
+
+// newImageWithTimeout creates an Image from the path or URL, fetching the URL within fetchTimeout.
+func newImageWithTimeout(pathOrURL string, fetchTimeout time.Duration) (_ *Image, err error) {
 	var b io.Reader
 	var modTime time.Time
 	if strings.HasPrefix(pathOrURL, "http://") || strings.HasPrefix(pathOrURL, "https://") {
@@ -76,7 +94,7 @@ func NewImage(pathOrURL string) (_ *Image, err error) {
 		}
 
 		client := &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: fetchTimeout,
 		}
 		req, err := http.NewRequest("GET", pathOrURL, nil)
 		if err != nil {
@@ -89,7 +107,7 @@ func NewImage(pathOrURL string) (_ *Image, err error) {
 		}
 		defer res.Body.Close()
 		if res.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("failed to fetch image from URL %s: status code %d", pathOrURL, res.StatusCode)
+			return nil, &imageFetchStatusError{url: pathOrURL, statusCode: res.StatusCode}
 		}
 		b = res.Body
 	} else {
@@ -111,18 +129,27 @@ func NewImage(pathOrURL string) (_ *Image, err error) {
 		defer file.Close()
 		b = file
 	}
-	i, err := newImageFromBuffer(b)
+	i, err := newImageFromSource(pathOrURL, b, modTime)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create image from buffer: %w", err)
+		return nil, err
+	}
+	StoreImageCache(pathOrURL, i)
+	return i, nil
+}
+
+// newImageFromSource creates an Image from the data read from r, which was read from the path or URL.
+func newImageFromSource(pathOrURL string, r io.Reader, modTime time.Time) (*Image, error) {
+	i, err := newImageFromBuffer(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image from %s: %w", pathOrURL, err)
 	}
 	i.url = pathOrURL
-	if isPublicURL(pathOrURL) {
+	if isPublicURL(pathOrURL) && !i.transcoded {
 		// If the URL appears to be OK for direct access, `deck` will not upload a temporary image to Google Drive
 		// but will instead specify that URL directly in the CreateImageRequest.
 		i.webContentLink = pathOrURL
 	}
 	i.modTime = modTime
-	StoreImageCache(pathOrURL, i)
 	return i, nil
 }
 
@@ -158,6 +185,35 @@ func newImageFromBuffer(r io.Reader) (_ *Image, err error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read image data: %w", err)
 	}
+	if isSVG(b) {
+		// The Slides API does not render SVG images, so rasterize them to PNG.
+		rasterized, err := rasterizeSVG(b, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Image{
+			b:          rasterized,
+			mimeType:   MIMETypeImagePNG,
+			transcoded: true,
+			svg:        b,
+		}, nil
+	}
+	if isHEIC(b) {
+		// The Slides API does not accept HEIC/HEIF images, so transcode them to JPEG.
+		transcoded, err := transcodeHEIC(b)
+		if err != nil {
+			return nil, err
+		}
+		return &Image{
+			b:          transcoded,
+			mimeType:   MIMETypeImageJPEG,
+			transcoded: true,
+		}, nil
+	}
+	if ct := http.DetectContentType(b); !strings.HasPrefix(ct, "image/") {
+		// e.g., an HTML error page returned instead of the image.
+		return nil, fmt.Errorf("content is not an image: %s", ct)
+	}
 	_, mimeType, err := image.DecodeConfig(bytes.NewReader(b))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode image: %w", err)
@@ -170,6 +226,21 @@ func newImageFromBuffer(r io.Reader) (_ *Image, err error) {
 		mt = MIMETypeImageJPEG
 	case "gif":
 		mt = MIMETypeImageGIF
+	case "webp":
+		// The Slides API does not accept WebP images, so transcode them to PNG.
+		img, err := webp.Decode(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode webp image: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to transcode webp image to png: %w", err)
+		}
+		return &Image{
+			b:          buf.Bytes(),
+			mimeType:   MIMETypeImagePNG,
+			transcoded: true,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported image MIME type: %s", mimeType)
 	}
@@ -183,6 +254,26 @@ func (i *Image) SetLink(link string) {
 	i.link = link
 }
 
+// SetSlot sets the name of the image placeholder to place the image in.
+func (i *Image) SetSlot(slot string) {
+	i.slot = slot
+}
+
+// Slot returns the name of the image placeholder to place the image in.
+func (i *Image) Slot() string {
+	return i.slot
+}
+
+// SetAlt sets the alternative text of the image.
+func (i *Image) SetAlt(alt string) {
+	i.alt = alt
+}
+
+// Alt returns the alternative text of the image.
+func (i *Image) Alt() string {
+	return i.alt
+}
+
 func (i *Image) Equivalent(ii *Image) bool {
 	if i == nil || ii == nil {
 		return false
@@ -286,6 +377,11 @@ type internalImage struct {
 	FromMarkdown bool
 	ModTime      time.Time
 	Link         string
+	Slot         string `json:",omitempty"`
+	Alt          string `json:",omitempty"`
+	Width        string `json:",omitempty"`
+	Height       string `json:",omitempty"`
+	SVG          []byte `json:",omitempty"`
 }
 
 // MarshalJSON and UnmarshalJSON are defined for cloning data and for similarity comparisons of `slide` structures.
@@ -378,6 +474,11 @@ func (i *Image) toInternal() *internalImage {
 		FromMarkdown: i.fromMarkdown,
 		ModTime:      i.modTime,
 		Link:         i.link,
+		Slot:         i.slot,
+		Alt:          i.alt,
+		Width:        i.width,
+		Height:       i.height,
+		SVG:          i.svg,
 	}
 }
 
@@ -386,6 +487,11 @@ func (iimg *internalImage) toImage(i *Image) error {
 	i.fromMarkdown = iimg.FromMarkdown
 	i.modTime = iimg.ModTime
 	i.link = iimg.Link
+	i.slot = iimg.Slot
+	i.alt = iimg.Alt
+	i.width = iimg.Width
+	i.height = iimg.Height
+	i.svg = iimg.SVG
 
 	data := []byte(iimg.Data)
 	if !bytes.HasPrefix(data, []byte(`data:`)) {
@@ -411,6 +517,16 @@ func (iimg *internalImage) toImage(i *Image) error {
 	return nil
 }
 
+// imageFetchStatusError is returned when fetching an image from a URL responds with a status other than 200 OK.
+type imageFetchStatusError struct {
+	url        string
+	statusCode int
+}
+
+func (e *imageFetchStatusError) Error() string {
+	return fmt.Sprintf("failed to fetch image from URL %s: status code %d", e.url, e.statusCode)
+}
+
 // isPublicURL checks whether a URL string is OK for direct public access.
 // Since we only need to identify what appear to be public URLs, false negatives are acceptable.
 func isPublicURL(rawURL string) bool {
@@ -0,0 +1,92 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// ImageAnchor is the position within the content area where an auto-placed image sits.
+type ImageAnchor string
+
+const (
+	ImageAnchorCenter ImageAnchor = "center"
+	ImageAnchorTop    ImageAnchor = "top"
+	ImageAnchorBottom ImageAnchor = "bottom"
+	ImageAnchorLeft   ImageAnchor = "left"
+	ImageAnchorRight  ImageAnchor = "right"
+)
+
+const (
+	emuPerPixel       = 9525    // 1px at 96 DPI
+	imageAreaMargin   = 365760  // 0.4 inch on every side of the content area
+	defaultPageWidth  = 9144000 // 16:9 page of Google Slides
+	defaultPageHeight = 5143500
+)
+
+// rect is a rectangle in EMU.
+type rect struct {
+	x, y, width, height float64
+}
+
+func validateImageAnchor(anchor ImageAnchor) error {
+	switch anchor {
+	case ImageAnchorCenter, ImageAnchorTop, ImageAnchorBottom, ImageAnchorLeft, ImageAnchorRight:
+		return nil
+	default:
+		return fmt.Errorf("invalid image anchor: %s", anchor)
+	}
+}
+
+// imageContentArea returns the area of the page in which images are auto-placed.
+func (d *Deck) imageContentArea() rect {
+	width, height := d.pageSize()
+	return rect{
+		x:      imageAreaMargin,
+		y:      imageAreaMargin,
+		width:  width - 2*imageAreaMargin,
+		height: height - 2*imageAreaMargin,
+	}
+}
+
+// anchorImage returns the size and transform of an image of w x h pixels fitted into area
+// preserving the aspect ratio, and positioned at anchor.
+func anchorImage(area rect, w, h int, anchor ImageAnchor) (*slides.Size, *slides.AffineTransform) {
+	width, height := float64(w*emuPerPixel), float64(h*emuPerPixel)
+	scale := min(area.width/width, area.height/height)
+	width, height = width*scale, height*scale
+
+	x := area.x + (area.width-width)/2
+	y := area.y + (area.height-height)/2
+	switch anchor {
+	case ImageAnchorTop:
+		y = area.y
+	case ImageAnchorBottom:
+		y = area.y + area.height - height
+	case ImageAnchorLeft:
+		x = area.x
+	case ImageAnchorRight:
+		x = area.x + area.width - width
+	}
+	return &slides.Size{
+		Width:  &slides.Dimension{Magnitude: width, Unit: "EMU"},
+		Height: &slides.Dimension{Magnitude: height, Unit: "EMU"},
+	}, &slides.AffineTransform{
+		ScaleX:     1.0,
+		ScaleY:     1.0,
+		TranslateX: x,
+		TranslateY: y,
+		Unit:       "EMU",
+	}
+}
+
+// imageDimensions returns the width and height in pixels of the image.
+func imageDimensions(i *Image) (int, int, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(i.Bytes()))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	return cfg.Width, cfg.Height, nil
+}
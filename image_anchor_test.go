@@ -0,0 +1,87 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestAnchorImage(t *testing.T) {
+	area := rect{x: 100, y: 200, width: 1000 * emuPerPixel, height: 1000 * emuPerPixel}
+	// A 2:1 image fits the width of the square area.
+	w, h := 400, 200
+	width, height := float64(1000*emuPerPixel), float64(500*emuPerPixel)
+
+	tests := []struct {
+		anchor ImageAnchor
+		x, y   float64
+	}{
+		{ImageAnchorCenter, 100, 200 + (area.height-height)/2},
+		{ImageAnchorTop, 100, 200},
+		{ImageAnchorBottom, 100, 200 + area.height - height},
+		{ImageAnchorLeft, 100, 200 + (area.height-height)/2},
+		{ImageAnchorRight, 100, 200 + (area.height-height)/2},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.anchor), func(t *testing.T) {
+			size, transform := anchorImage(area, w, h, tt.anchor)
+			if size.Width.Magnitude != width || size.Height.Magnitude != height {
+				t.Errorf("got size %vx%v, want %vx%v", size.Width.Magnitude, size.Height.Magnitude, width, height)
+			}
+			if diff := cmp.Diff([]float64{tt.x, tt.y}, []float64{transform.TranslateX, transform.TranslateY}); diff != "" {
+				t.Errorf("position mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// A 1:2 image fits the height, so left and right anchors move it horizontally.
+	_, left := anchorImage(area, 200, 400, ImageAnchorLeft)
+	_, right := anchorImage(area, 200, 400, ImageAnchorRight)
+	if left.TranslateX != 100 || right.TranslateX != 100+area.width-500*emuPerPixel {
+		t.Errorf("got x %v and %v for left and right anchors", left.TranslateX, right.TranslateX)
+	}
+}
+
+func TestWithImageAnchor(t *testing.T) {
+	d, err := newDeckWithOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.imageAnchor != ImageAnchorCenter {
+		t.Errorf("got default %s, want %s", d.imageAnchor, ImageAnchorCenter)
+	}
+	if err := WithImageAnchor(ImageAnchorTop)(d); err != nil {
+		t.Fatal(err)
+	}
+	if d.imageAnchor != ImageAnchorTop {
+		t.Errorf("got %s, want %s", d.imageAnchor, ImageAnchorTop)
+	}
+	if err := WithImageAnchor("middle")(d); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestImageContentAreaFollowsPageSize(t *testing.T) {
+	deckWithPageSize := func(width, height float64) *Deck {
+		return &Deck{presentation: &slides.Presentation{PageSize: &slides.Size{
+			Width:  &slides.Dimension{Magnitude: width, Unit: "EMU"},
+			Height: &slides.Dimension{Magnitude: height, Unit: "EMU"},
+		}}}
+	}
+	wide := deckWithPageSize(9144000, 5143500)     // 16:9
+	standard := deckWithPageSize(9144000, 6858000) // 4:3
+
+	wideSize, _ := anchorImage(wide.imageContentArea(), 1000, 1000, ImageAnchorCenter)
+	standardSize, _ := anchorImage(standard.imageContentArea(), 1000, 1000, ImageAnchorCenter)
+	if wideSize.Height.Magnitude >= standardSize.Height.Magnitude {
+		t.Errorf("got height %v on 16:9 and %v on 4:3, want the image to be larger on the taller page",
+			wideSize.Height.Magnitude, standardSize.Height.Magnitude)
+	}
+	if want := 5143500.0 - 2*imageAreaMargin; wideSize.Height.Magnitude != want {
+		t.Errorf("got height %v on 16:9, want %v", wideSize.Height.Magnitude, want)
+	}
+	if want := 6858000.0 - 2*imageAreaMargin; standardSize.Height.Magnitude != want {
+		t.Errorf("got height %v on 4:3, want %v", standardSize.Height.Magnitude, want)
+	}
+}
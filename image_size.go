@@ -0,0 +1,182 @@
+package deck
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// imageLength is a length of an image in points, or in percent of the page.
+type imageLength struct {
+	value   float64
+	percent bool
+}
+
+// parseImageLength parses a length such as "300", "300pt" or "50%".
+func parseImageLength(s string) (imageLength, error) {
+	v := strings.TrimSpace(s)
+	var l imageLength
+	switch {
+	case strings.HasSuffix(v, "%"):
+		l.percent = true
+		v = strings.TrimSuffix(v, "%")
+	case strings.HasSuffix(v, "pt"):
+		v = strings.TrimSuffix(v, "pt")
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 || math.IsInf(f, 0) {
+		return imageLength{}, fmt.Errorf("invalid image length: %q must be a positive number of points (e.g., 300 or 300pt) or percent (e.g., 50%%)", s)
+	}
+	l.value = f
+	return l, nil
+}
+
+// emu returns the length in EMU, where percent is relative to the page length.
+func (l imageLength) emu(page float64) float64 {
+	if l.percent {
+		return page * l.value / 100
+	}
+	return l.value * emuPerPoint
+}
+
+// SetSize sets the size of the image on the slide. width and height are in points (e.g., "300" or "300pt")
+// or in percent of the page width and height (e.g., "50%"). If one of them is empty, it is derived from
+// the other keeping the aspect ratio of the image.
+func (i *Image) SetSize(width, height string) error {
+	for _, v := range []string{width, height} {
+		if v == "" {
+			continue
+		}
+		if _, err := parseImageLength(v); err != nil {
+			return err
+		}
+	}
+	i.width, i.height = width, height
+	return nil
+}
+
+// Size returns the width and height of the image set by SetSize.
+func (i *Image) Size() (width, height string) {
+	return i.width, i.height
+}
+
+// pageSize returns the width and height of the pages of the presentation in EMU.
+func (d *Deck) pageSize() (float64, float64) {
+	if d.presentation != nil && d.presentation.PageSize != nil &&
+		d.presentation.PageSize.Width != nil && d.presentation.PageSize.Height != nil {
+		return d.presentation.PageSize.Width.Magnitude, d.presentation.PageSize.Height.Magnitude
+	}
+	return defaultPageWidth, defaultPageHeight
+}
+
+// imageSize returns the size of the image on the slide set by SetSize, or nil if it is not set.
+func (d *Deck) imageSize(i *Image) (*slides.Size, error) {
+	if i.width == "" && i.height == "" {
+		return nil, nil
+	}
+	pageWidth, pageHeight := d.pageSize()
+	var width, height float64
+	if i.width != "" {
+		l, err := parseImageLength(i.width)
+		if err != nil {
+			return nil, err
+		}
+		width = l.emu(pageWidth)
+	}
+	if i.height != "" {
+		l, err := parseImageLength(i.height)
+		if err != nil {
+			return nil, err
+		}
+		height = l.emu(pageHeight)
+	}
+	if width == 0 || height == 0 {
+		w, h, err := imageDimensions(i)
+		if err != nil {
+			return nil, err
+		}
+		if width == 0 {
+			width = height * float64(w) / float64(h)
+		} else {
+			height = width * float64(h) / float64(w)
+		}
+	}
+	return &slides.Size{
+		Width:  &slides.Dimension{Magnitude: width, Unit: "EMU"},
+		Height: &slides.Dimension{Magnitude: height, Unit: "EMU"},
+	}, nil
+}
+
+// readImageSizes sets the size of each of the current images to the size set for an equivalent image
+// of the desired slides, if the current image already has that size on the slide.
+// Then, only the images to resize make the current and desired slides differ.
+func (d *Deck) readImageSizes(current, desired Slides) error {
+	for _, cs := range current {
+		for _, ci := range cs.Images {
+			if ci.element == nil {
+				continue
+			}
+			for _, ds := range desired {
+				for _, di := range ds.Images {
+					if di.width == "" && di.height == "" || !ci.Equivalent(di) {
+						continue
+					}
+					size, err := d.imageSize(di)
+					if err != nil {
+						return err
+					}
+					if resizeImageRequest(ci.element, size) == nil {
+						ci.width, ci.height = di.width, di.height
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resizeImageRequest returns the request to scale the image element to size keeping its position,
+// or nil if the element already has the size.
+func resizeImageRequest(element *slides.PageElement, size *slides.Size) *slides.Request {
+	if element.Size == nil || element.Size.Width == nil || element.Size.Height == nil ||
+		element.Size.Width.Magnitude == 0 || element.Size.Height.Magnitude == 0 {
+		return nil
+	}
+	t := element.Transform
+	if t == nil {
+		t = &slides.AffineTransform{ScaleX: 1, ScaleY: 1}
+	}
+	scaleX := size.Width.Magnitude / element.Size.Width.Magnitude
+	scaleY := size.Height.Magnitude / element.Size.Height.Magnitude
+	// Sizes within 1 EMU are the same, as the API rounds them.
+	if math.Abs(scaleX-t.ScaleX)*element.Size.Width.Magnitude < 1 &&
+		math.Abs(scaleY-t.ScaleY)*element.Size.Height.Magnitude < 1 && t.ShearX == 0 && t.ShearY == 0 {
+		return nil
+	}
+	return &slides.Request{
+		UpdatePageElementTransform: &slides.UpdatePageElementTransformRequest{
+			ObjectId:  element.ObjectId,
+			ApplyMode: "ABSOLUTE",
+			Transform: &slides.AffineTransform{
+				ScaleX:     scaleX,
+				ScaleY:     scaleY,
+				TranslateX: t.TranslateX,
+				TranslateY: t.TranslateY,
+				Unit:       "EMU",
+			},
+		},
+	}
+}
+
+// pageElementByID returns the page element of the page with the object ID, or nil if there is none.
+func pageElementByID(page *slides.Page, objectID string) *slides.PageElement {
+	for _, element := range page.PageElements {
+		if element.ObjectId == objectID {
+			return element
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package deck
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestImageSize(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 300, 200))); err != nil {
+		t.Fatal(err)
+	}
+	d := &Deck{presentation: &slides.Presentation{PageSize: &slides.Size{
+		Width:  &slides.Dimension{Magnitude: 9144000, Unit: "EMU"},
+		Height: &slides.Dimension{Magnitude: 5143500, Unit: "EMU"},
+	}}}
+	tests := []struct {
+		width, height string
+		wantW, wantH  float64
+		wantErr       bool
+	}{
+		{"", "", 0, 0, false},
+		{"300", "", 300 * emuPerPoint, 200 * emuPerPoint, false},
+		{"", "100pt", 150 * emuPerPoint, 100 * emuPerPoint, false},
+		{"50%", "", 4572000, 3048000, false},
+		{"50%", "25%", 4572000, 1285875, false},
+		{"0", "", 0, 0, true},
+		{"-10", "", 0, 0, true},
+		{"wide", "", 0, 0, true},
+	}
+	for _, tt := range tests {
+		i := &Image{b: buf.Bytes(), mimeType: MIMETypeImagePNG}
+		if err := i.SetSize(tt.width, tt.height); err != nil {
+			if !tt.wantErr {
+				t.Errorf("SetSize(%q, %q) error: %v", tt.width, tt.height, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("SetSize(%q, %q) expected error but got none", tt.width, tt.height)
+			continue
+		}
+		got, err := d.imageSize(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.wantW == 0 {
+			if got != nil {
+				t.Errorf("imageSize(%q, %q) = %v, want nil", tt.width, tt.height, got)
+			}
+			continue
+		}
+		if math.Abs(got.Width.Magnitude-tt.wantW) > 0.5 || math.Abs(got.Height.Magnitude-tt.wantH) > 0.5 {
+			t.Errorf("imageSize(%q, %q) = %vx%v, want %vx%v", tt.width, tt.height,
+				got.Width.Magnitude, got.Height.Magnitude, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestResizeImageRequest(t *testing.T) {
+	element := &slides.PageElement{
+		ObjectId: "image-1",
+		Size: &slides.Size{
+			Width:  &slides.Dimension{Magnitude: 1000, Unit: "EMU"},
+			Height: &slides.Dimension{Magnitude: 500, Unit: "EMU"},
+		},
+		Transform: &slides.AffineTransform{ScaleX: 2, ScaleY: 2, TranslateX: 10, TranslateY: 20, Unit: "EMU"},
+	}
+	size := func(w, h float64) *slides.Size {
+		return &slides.Size{
+			Width:  &slides.Dimension{Magnitude: w, Unit: "EMU"},
+			Height: &slides.Dimension{Magnitude: h, Unit: "EMU"},
+		}
+	}
+	if req := resizeImageRequest(element, size(2000, 1000)); req != nil {
+		t.Errorf("image of the same size should not be resized: %+v", req.UpdatePageElementTransform)
+	}
+	req := resizeImageRequest(element, size(3000, 1500))
+	if req == nil {
+		t.Fatal("expected request but got nil")
+	}
+	got := req.UpdatePageElementTransform
+	if got.ObjectId != "image-1" || got.ApplyMode != "ABSOLUTE" ||
+		got.Transform.ScaleX != 3 || got.Transform.ScaleY != 3 ||
+		got.Transform.TranslateX != 10 || got.Transform.TranslateY != 20 {
+		t.Errorf("got %+v, %+v", got, got.Transform)
+	}
+}
+
+func TestApplyResizesImage(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(ts.Close)
+	tests := []struct {
+		name   string
+		scale  float64
+		resize bool
+	}{
+		{"size changed", 0.5, true},
+		{"size unchanged", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeServer{presentation: newFakePresentation("a")}
+			s.presentation.Slides[0].PageElements = append(s.presentation.Slides[0].PageElements, &slides.PageElement{
+				ObjectId:    "image",
+				Description: descriptionImageFromMarkdown,
+				Size: &slides.Size{
+					Width:  &slides.Dimension{Magnitude: 100 * emuPerPoint, Unit: "EMU"},
+					Height: &slides.Dimension{Magnitude: 100 * emuPerPoint, Unit: "EMU"},
+				},
+				Transform: &slides.AffineTransform{ScaleX: tt.scale, ScaleY: tt.scale, Unit: "EMU"},
+				Image:     &slides.Image{ContentUrl: ts.URL + "/test.png"},
+			})
+			d := newFakeDeck(t, s, WithStorage(&countingStorage{}))
+			i, err := NewImageFromMarkdown("testdata/test.png")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := i.SetSize("100pt", "100pt"); err != nil {
+				t.Fatal(err)
+			}
+
+			ss := Slides{{Layout: "Title and Content", Titles: []string{"a"}, Images: []*Image{i}}}
+			if _, err := d.Apply(t.Context(), ss); err != nil {
+				t.Fatal(err)
+			}
+			var resized bool
+			for _, req := range s.batchUpdates {
+				for _, r := range req.Requests {
+					if r.UpdatePageElementTransform != nil && r.UpdatePageElementTransform.ObjectId == "image" {
+						resized = true
+					}
+				}
+			}
+			if resized != tt.resize {
+				t.Errorf("got resized %v, want %v", resized, tt.resize)
+			}
+			if !tt.resize && len(s.batchUpdates) != 0 {
+				t.Errorf("got %d batch updates, want 0", len(s.batchUpdates))
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package deck
+
+import "google.golang.org/api/slides/v1"
+
+// placeholderName returns the name of the placeholder, which is the alt text title of the element
+// or of the placeholder of the layout that the element inherits from.
+func placeholderName(element *slides.PageElement, layout *slides.Page) string {
+	if element.Title != "" {
+		return element.Title
+	}
+	var parentID string
+	switch {
+	case element.Image != nil && element.Image.Placeholder != nil:
+		parentID = element.Image.Placeholder.ParentObjectId
+	case element.Shape != nil && element.Shape.Placeholder != nil:
+		parentID = element.Shape.Placeholder.ParentObjectId
+	}
+	if parentID == "" || layout == nil {
+		return ""
+	}
+	for _, e := range layout.PageElements {
+		if e.ObjectId == parentID {
+			return e.Title
+		}
+	}
+	return ""
+}
+
+// assignImagePlaceholders returns the image placeholder to place each of the images in, or nil if there is none.
+// Images with a slot are placed in the placeholder of that name, and the other images are placed
+// in the remaining placeholders in order.
+func assignImagePlaceholders(images []*Image, placeholders []placeholder) []*placeholder {
+	slots := make([]*placeholder, len(images))
+	used := make([]bool, len(placeholders))
+	for i, image := range images {
+		if image.slot == "" {
+			continue
+		}
+		for j := range placeholders {
+			if !used[j] && placeholders[j].name == image.slot {
+				slots[i] = &placeholders[j]
+				used[j] = true
+				break
+			}
+		}
+	}
+	j := 0
+	for i := range images {
+		if slots[i] != nil {
+			continue
+		}
+		for j < len(placeholders) && used[j] {
+			j++
+		}
+		if j == len(placeholders) {
+			break
+		}
+		slots[i] = &placeholders[j]
+		used[j] = true
+	}
+	return slots
+}
@@ -0,0 +1,88 @@
+package deck
+
+import (
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestAssignImagePlaceholders(t *testing.T) {
+	placeholders := []placeholder{
+		{objectID: "left", name: "left"},
+		{objectID: "right", name: "right"},
+		{objectID: "unnamed"},
+	}
+	a, b, c := &Image{}, &Image{}, &Image{}
+	a.SetSlot("right")
+	c.SetSlot("left")
+
+	slots := assignImagePlaceholders([]*Image{a, b, c}, placeholders)
+	var got []string
+	for _, s := range slots {
+		got = append(got, s.objectID)
+	}
+	want := []string{"right", "unnamed", "left"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+
+	// Without slots, images are placed in order and the rest are not placed in placeholders.
+	slots = assignImagePlaceholders([]*Image{{}, {}, {}, {}}, placeholders)
+	if slots[0].objectID != "left" || slots[1].objectID != "right" || slots[2].objectID != "unnamed" || slots[3] != nil {
+		t.Errorf("unexpected positional assignment: %v", slots)
+	}
+}
+
+func TestPlaceholderName(t *testing.T) {
+	layout := &slides.Page{PageElements: []*slides.PageElement{
+		{ObjectId: "layout-image-left", Title: "left"},
+	}}
+	inherited := &slides.PageElement{Image: &slides.Image{
+		Placeholder: &slides.Placeholder{Type: "PICTURE", ParentObjectId: "layout-image-left"},
+	}}
+	if got := placeholderName(inherited, layout); got != "left" {
+		t.Errorf("got %q, want %q", got, "left")
+	}
+	own := &slides.PageElement{Title: "right", Image: inherited.Image}
+	if got := placeholderName(own, layout); got != "right" {
+		t.Errorf("got %q, want %q", got, "right")
+	}
+}
+
+func TestApplyPagesPlacesImageInSlot(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	for i, name := range []string{"left", "right"} {
+		s.presentation.Slides[0].PageElements = append(s.presentation.Slides[0].PageElements, &slides.PageElement{
+			ObjectId:  "image-" + name,
+			Title:     name,
+			Transform: &slides.AffineTransform{TranslateX: float64(i) * 100},
+			Image:     &slides.Image{Placeholder: &slides.Placeholder{Type: "PICTURE"}},
+		})
+	}
+	d := newFakeDeck(t, s, WithStorage(&countingStorage{}))
+	image, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	image.SetSlot("right")
+
+	ss := Slides{{Layout: "Title and Content", Titles: []string{"b"}, Images: []*Image{image}}}
+	if _, err := d.ApplyPages(t.Context(), ss, []int{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var replaced []string
+	for _, req := range s.batchUpdates {
+		for _, r := range req.Requests {
+			if r.ReplaceImage != nil {
+				replaced = append(replaced, r.ReplaceImage.ImageObjectId)
+			}
+		}
+	}
+	if len(replaced) != 1 || replaced[0] != "image-right" {
+		t.Errorf("got replaced images %q, want %q", replaced, []string{"image-right"})
+	}
+}
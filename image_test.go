@@ -5,6 +5,10 @@ import (
 	"image"
 	"image/color"
 	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -61,3 +65,61 @@ func TestNewImageFromCodeBlock(t *testing.T) {
 		t.Errorf("Image.codeBlock() = %v, want true", got)
 	}
 }
+
+func TestNewImageTranscodesWebP(t *testing.T) {
+	b, err := os.ReadFile("testdata/test.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(ts.Close)
+
+	for _, pathOrURL := range []string{"testdata/test.webp", ts.URL + "/test.webp"} {
+		i, err := NewImage(pathOrURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.mimeType != MIMETypeImagePNG {
+			t.Errorf("got MIME type %q, want %q", i.mimeType, MIMETypeImagePNG)
+		}
+		if _, format, err := image.DecodeConfig(bytes.NewReader(i.Bytes())); err != nil || format != "png" {
+			t.Errorf("image data should be png: format %q, error %v", format, err)
+		}
+		if !i.IsUploadNeeded() {
+			t.Error("transcoded image should be uploaded instead of using the URL directly")
+		}
+	}
+}
+
+func TestNewImageRejectsNonImageContent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<!DOCTYPE html><html><body>Not Found</body></html>"))
+	}))
+	t.Cleanup(ts.Close)
+
+	url := ts.URL + "/missing.png"
+	_, err := NewImage(url)
+	if err == nil {
+		t.Fatal("expected error for HTML content but got none")
+	}
+	if !strings.Contains(err.Error(), url) || !strings.Contains(err.Error(), "text/html") {
+		t.Errorf("error should name the URL and the content type: %v", err)
+	}
+}
+
+func TestImageUploadDataChecksContentType(t *testing.T) {
+	i, err := NewImageFromCodeBlock(dummyPNG(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &Deck{}
+	if _, _, err := d.imageUploadData(i); err != nil {
+		t.Fatal(err)
+	}
+	i.mimeType = MIMETypeImageJPEG
+	if _, _, err := d.imageUploadData(i); err == nil {
+		t.Error("expected error for mismatched content type but got none")
+	}
+}
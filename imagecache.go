@@ -0,0 +1,208 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultImageCacheTTL = 24 * time.Hour
+
+// imageCacheMaxAge is the age after which entries are pruned even if they can still be revalidated.
+const imageCacheMaxAge = 30 * 24 * time.Hour
+
+// imageCache is an on-disk cache of images fetched from URLs.
+// Entries are keyed by the URL and image data is stored content-addressed by the SHA-256 of the bytes,
+// so identical images fetched from different URLs share the same data file.
+// Entries that can no longer be used and the data no longer referenced are pruned on the first store of each cache.
+type imageCache struct {
+	dir    string
+	ttl    time.Duration
+	client *http.Client
+	hits   atomic.Int64
+	misses atomic.Int64
+	prune  sync.Once
+}
+
+// imageCacheEntry is the metadata of a cached image.
+type imageCacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Data         string    `json:"data"` // SHA-256 of the image bytes
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+func newImageCache(dir string, ttl, fetchTimeout time.Duration) *imageCache {
+	return &imageCache{
+		dir: dir,
+		ttl: ttl,
+		client: &http.Client{
+			Timeout: fetchTimeout,
+		},
+	}
+}
+
+// newImage creates an Image from the URL, using the cached image if it is still fresh.
+func (c *imageCache) newImage(ctx context.Context, rawURL string, fromMarkdown bool) (*Image, error) {
+	b, err := c.get(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	i, err := newImageFromSource(rawURL, bytes.NewReader(b), time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	i.fromMarkdown = fromMarkdown
+	return i, nil
+}
+
+// get returns the image bytes of the URL.
+// A fresh entry is returned without network access. An expired entry with an ETag or Last-Modified is
+// revalidated with a conditional request, and the image is downloaded only when the remote has changed.
+func (c *imageCache) get(ctx context.Context, rawURL string) ([]byte, error) {
+	entry, data := c.load(rawURL)
+	if entry != nil && time.Since(entry.FetchedAt) < c.ttl {
+		c.hits.Add(1)
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image from URL %s: %w", rawURL, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if entry != nil && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry != nil && entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image from URL %s: %w", rawURL, err)
+	}
+	defer res.Body.Close()
+	switch {
+	case res.StatusCode == http.StatusNotModified && entry != nil:
+		c.hits.Add(1)
+		entry.FetchedAt = time.Now()
+		_ = c.storeEntry(entry)
+		return data, nil
+	case res.StatusCode != http.StatusOK:
+		return nil, &imageFetchStatusError{url: rawURL, statusCode: res.StatusCode}
+	}
+	c.misses.Add(1)
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image from URL %s: %w", rawURL, err)
+	}
+	// Failure to write the cache should not fail fetching the image.
+	_ = c.store(rawURL, res.Header.Get("ETag"), res.Header.Get("Last-Modified"), b)
+	return b, nil
+}
+
+func (c *imageCache) load(rawURL string) (*imageCacheEntry, []byte) {
+	b, err := os.ReadFile(c.entryPath(rawURL))
+	if err != nil {
+		return nil, nil
+	}
+	entry := &imageCacheEntry{}
+	if err := json.Unmarshal(b, entry); err != nil || entry.URL != rawURL {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, entry.Data))
+	if err != nil || hashHex(data) != entry.Data {
+		return nil, nil
+	}
+	return entry, data
+}
+
+func (c *imageCache) store(rawURL, etag, lastModified string, data []byte) error {
+	if err := mkdirState(c.dir); err != nil {
+		return err
+	}
+	c.prune.Do(c.pruneUnusable)
+	entry := &imageCacheEntry{
+		URL:          rawURL,
+		ETag:         etag,
+		LastModified: lastModified,
+		Data:         hashHex(data),
+		FetchedAt:    time.Now(),
+	}
+	if err := os.WriteFile(filepath.Join(c.dir, entry.Data), data, 0600); err != nil {
+		return err
+	}
+	return c.storeEntry(entry)
+}
+
+func (c *imageCache) storeEntry(entry *imageCacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.entryPath(entry.URL), b, 0600)
+}
+
+// pruneUnusable removes the entries that can no longer be used and the data files that no entry refers to.
+// Expired entries are kept as long as they can be revalidated, up to imageCacheMaxAge.
+// Errors are ignored, as the cache works without pruning.
+func (c *imageCache) pruneUnusable() {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	referenced := map[string]struct{}{}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		p := filepath.Join(c.dir, f.Name())
+		b, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		entry := &imageCacheEntry{}
+		if err := json.Unmarshal(b, entry); err != nil || !entry.usable(c.ttl) {
+			_ = os.Remove(p)
+			continue
+		}
+		referenced[entry.Data] = struct{}{}
+	}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) == ".json" {
+			continue
+		}
+		if _, ok := referenced[f.Name()]; !ok {
+			_ = os.Remove(filepath.Join(c.dir, f.Name()))
+		}
+	}
+}
+
+// usable reports whether the entry is fresh, or expired but can be revalidated and is not too old.
+func (e *imageCacheEntry) usable(ttl time.Duration) bool {
+	age := time.Since(e.FetchedAt)
+	if age < ttl {
+		return true
+	}
+	return (e.ETag != "" || e.LastModified != "") && age < imageCacheMaxAge
+}
+
+func (c *imageCache) entryPath(rawURL string) string {
+	return filepath.Join(c.dir, hashHex([]byte(rawURL))+".json")
+}
+
+func hashHex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
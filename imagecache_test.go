@@ -0,0 +1,124 @@
+package deck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestImageCache(t *testing.T) {
+	data := dummyPNG(t).Bytes()
+	var requests, downloads atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(ts.Close)
+
+	c := newImageCache(t.TempDir(), time.Hour, defaultImageFetchTimeout)
+	url := ts.URL + "/image.png"
+
+	for range 2 {
+		i, err := c.newImage(t.Context(), url, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !i.fromMarkdown || i.url != url {
+			t.Errorf("got fromMarkdown %v and url %q", i.fromMarkdown, i.url)
+		}
+		if i.Checksum() != (&Image{b: data}).Checksum() {
+			t.Error("cached image data mismatch")
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+	if c.hits.Load() != 1 || c.misses.Load() != 1 {
+		t.Errorf("got hits %d and misses %d, want 1 and 1", c.hits.Load(), c.misses.Load())
+	}
+
+	// Expired entries are revalidated with the ETag and not downloaded again.
+	c.ttl = 0
+	if _, err := c.newImage(t.Context(), url, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+	if got := downloads.Load(); got != 1 {
+		t.Errorf("got %d downloads, want 1", got)
+	}
+	if c.hits.Load() != 2 {
+		t.Errorf("got hits %d, want 2", c.hits.Load())
+	}
+}
+
+func TestImageCachePrunesUnusableEntries(t *testing.T) {
+	dir := t.TempDir()
+	old := newImageCache(dir, time.Hour, defaultImageFetchTimeout)
+	for _, e := range []struct {
+		url, etag, lastModified string
+		age                     time.Duration
+	}{
+		{"https://example.com/old.png", "", "", 2 * time.Hour},
+		{"https://example.com/fresh.png", "", "", 0},
+		{"https://example.com/etag.png", `"v1"`, "", 2 * time.Hour},
+		{"https://example.com/last-modified.png", "", "Mon, 02 Jan 2006 15:04:05 GMT", 2 * time.Hour},
+		{"https://example.com/too-old.png", `"v1"`, "", imageCacheMaxAge},
+	} {
+		if err := old.store(e.url, e.etag, e.lastModified, []byte(e.url)); err != nil {
+			t.Fatal(err)
+		}
+		entry, _ := old.load(e.url)
+		entry.FetchedAt = time.Now().Add(-e.age)
+		if err := old.storeEntry(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newImageCache(dir, time.Hour, defaultImageFetchTimeout)
+	if err := c.store("https://example.com/new.png", "", "", []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []string{"https://example.com/old.png", "https://example.com/too-old.png"} {
+		if entry, _ := c.load(u); entry != nil {
+			t.Errorf("entry of %s was not pruned", u)
+		}
+		if _, err := os.Stat(filepath.Join(dir, hashHex([]byte(u)))); !os.IsNotExist(err) {
+			t.Errorf("data of %s was not pruned: %v", u, err)
+		}
+	}
+	// Expired entries that can be revalidated are kept.
+	for _, u := range []string{"https://example.com/fresh.png", "https://example.com/etag.png", "https://example.com/last-modified.png", "https://example.com/new.png"} {
+		if entry, _ := c.load(u); entry == nil {
+			t.Errorf("entry of %s was pruned", u)
+		}
+	}
+}
+
+func TestWithImageCache(t *testing.T) {
+	d, err := newDeckWithOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.imageCacheEnabled {
+		t.Error("image cache should be enabled by default")
+	}
+	d, err = newDeckWithOptions(WithImageCache(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.imageCacheEnabled {
+		t.Error("image cache should be disabled")
+	}
+}
@@ -0,0 +1,122 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+const imgurEndpoint = "https://api.imgur.com/3"
+
+// ImgurStorage implements Storage by uploading images anonymously to Imgur.
+// It is intended for personal use where no cloud storage is set up.
+// The uploaded ID is the delete hash of the image, so that Delete can remove it.
+type ImgurStorage struct {
+	clientID string
+	endpoint string
+	client   *http.Client
+}
+
+var _ Storage = (*ImgurStorage)(nil)
+
+// NewImgurStorage creates a new ImgurStorage with the client ID of an Imgur application.
+func NewImgurStorage(clientID string) *ImgurStorage {
+	return &ImgurStorage{
+		clientID: clientID,
+		endpoint: imgurEndpoint,
+		client:   http.DefaultClient,
+	}
+}
+
+type imgurResponse struct {
+	Data    json.RawMessage `json:"data"`
+	Success bool            `json:"success"`
+	Status  int             `json:"status"`
+}
+
+type imgurImage struct {
+	Link       string `json:"link"`
+	DeleteHash string `json:"deletehash"`
+}
+
+// Upload uploads the image to Imgur and returns its link and delete hash.
+func (s *ImgurStorage) Upload(ctx context.Context, data []byte, mimeType string) (publicURL, uploadedID string, err error) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	fw, err := w.CreateFormFile("image", "image")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		return "", "", fmt.Errorf("failed to write image data: %w", err)
+	}
+	if err := w.WriteField("type", "file"); err != nil {
+		return "", "", fmt.Errorf("failed to write form field: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/image", body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	res, err := s.do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to upload image to imgur: %w", err)
+	}
+	img := &imgurImage{}
+	if err := json.Unmarshal(res.Data, img); err != nil || img.Link == "" || img.DeleteHash == "" {
+		return "", "", fmt.Errorf("failed to upload image to imgur: no link or delete hash in response")
+	}
+	return img.Link, img.DeleteHash, nil
+}
+
+// Delete deletes the image by its delete hash.
+func (s *ImgurStorage) Delete(ctx context.Context, uploadedID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.endpoint+"/image/"+url.PathEscape(uploadedID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if _, err := s.do(req); err != nil {
+		return fmt.Errorf("failed to delete image from imgur: %w", err)
+	}
+	return nil
+}
+
+func (s *ImgurStorage) do(req *http.Request) (*imgurResponse, error) {
+	req.Header.Set("Authorization", "Client-ID "+s.clientID)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("imgur rate limit exceeded for the client (client remaining: %s, user remaining: %s); anonymous uploads are limited, try again later",
+			headerOrUnknown(resp.Header, "X-RateLimit-ClientRemaining"), headerOrUnknown(resp.Header, "X-RateLimit-UserRemaining"))
+	}
+	res := &imgurResponse{}
+	if err := json.Unmarshal(b, res); err != nil {
+		return nil, fmt.Errorf("unexpected response from imgur (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || !res.Success {
+		return nil, fmt.Errorf("imgur responded with status %d: %s", resp.StatusCode, res.Data)
+	}
+	return res, nil
+}
+
+func headerOrUnknown(h http.Header, key string) string {
+	if v := h.Get(key); v != "" {
+		return v
+	}
+	return "unknown"
+}
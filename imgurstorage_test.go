@@ -0,0 +1,67 @@
+package deck
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestImgurStorage(t *testing.T) {
+	var deleted string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Client-ID client-id" {
+			t.Errorf("got Authorization %q", got)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/image":
+			f, _, err := r.FormFile("image")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := io.ReadAll(f)
+			if string(b) != "png data" {
+				t.Errorf("got %q, want %q", b, "png data")
+			}
+			_, _ = io.WriteString(w, `{"data":{"link":"https://i.imgur.com/abc.png","deletehash":"hash"},"success":true,"status":200}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/image/hash":
+			deleted = "hash"
+			_, _ = io.WriteString(w, `{"data":true,"success":true,"status":200}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	s := NewImgurStorage("client-id")
+	s.endpoint = ts.URL
+
+	publicURL, id, err := s.Upload(t.Context(), []byte("png data"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicURL != "https://i.imgur.com/abc.png" || id != "hash" {
+		t.Errorf("got %q and %q", publicURL, id)
+	}
+	if err := s.Delete(t.Context(), id); err != nil {
+		t.Fatal(err)
+	}
+	if deleted != "hash" {
+		t.Errorf("image was not deleted")
+	}
+}
+
+func TestImgurStorageRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-ClientRemaining", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(ts.Close)
+	s := NewImgurStorage("client-id")
+	s.endpoint = ts.URL
+
+	_, _, err := s.Upload(t.Context(), []byte("png data"), "image/png")
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Errorf("got %v, want rate limit error", err)
+	}
+}
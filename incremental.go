@@ -0,0 +1,137 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// applyStatePath returns the path of the file to store the state of the last apply to the presentation by the profile in.
+var applyStatePath = func(profile, id string) string {
+	return filepath.Join(stateDir(profile), "applies", id+".json")
+}
+
+// applyState is the state of the presentation after the last apply.
+type applyState struct {
+	// RevisionID is the revision of the presentation after the apply.
+	// The hashes are valid only while the presentation stays at the revision.
+	RevisionID   string   `json:"revision_id"`
+	ManagedStart int      `json:"managed_start"`
+	Options      string   `json:"options"` // hash of the options that change the output, see optionsHash
+	Hashes       []string `json:"hashes"`  // content hash of each slide applied
+}
+
+// optionsHash returns the hash of the options of the Deck that change how the slides are applied,
+// so that changing any of them applies all the slides again.
+// The styles and the layouts come from the presentation, whose revision is checked separately.
+func (d *Deck) optionsHash() string {
+	b, _ := json.Marshal(struct {
+		DefaultTitleLayout  string
+		DefaultLayout       string
+		ImageAnchor         ImageAnchor
+		MaxImageDimension   int
+		SVGScale            float64
+		FlattenGIF          bool
+		WideTablePolicy     WideTablePolicy
+		WideTableMaxColumns int
+		PermanentImages     bool
+		PreserveEdited      bool
+	}{
+		DefaultTitleLayout:  d.defaultTitleLayout,
+		DefaultLayout:       d.defaultLayout,
+		ImageAnchor:         d.imageAnchor,
+		MaxImageDimension:   d.maxImageDimension,
+		SVGScale:            d.svgScale,
+		FlattenGIF:          d.flattenGIF,
+		WideTablePolicy:     d.wideTablePolicy,
+		WideTableMaxColumns: d.wideTableMaxColumns,
+		PermanentImages:     d.permanentImages,
+		PreserveEdited:      d.preserveEdited,
+	})
+	return hashHex(b)
+}
+
+// slideHashes returns the content hash of each of the slides.
+func slideHashes(ss Slides) []string {
+	hashes := make([]string, len(ss))
+	for i, s := range ss {
+		hashes[i] = Slides{s}.ContentHash()
+	}
+	return hashes
+}
+
+// unchangedPages returns the pages whose slides are unchanged since the last apply,
+// which is known only if the presentation and the options have not been modified since then.
+func (d *Deck) unchangedPages(pages []int, hashes []string) []int {
+	if !d.incrementalApply || d.forceFullApply || d.presentation.RevisionId == "" {
+		return nil
+	}
+	state, err := loadApplyState(d.profile, d.id)
+	if err != nil {
+		d.logger.Warn("failed to load the state of the last apply", slog.Any("error", err))
+		return nil
+	}
+	if state == nil || state.RevisionID != d.presentation.RevisionId || state.ManagedStart != d.managedStart ||
+		state.Options != d.optionsHash() {
+		return nil
+	}
+	var unchanged []int
+	for _, page := range pages {
+		i := page - 1
+		if i < len(state.Hashes) && state.Hashes[i] == hashes[i] {
+			unchanged = append(unchanged, page)
+		}
+	}
+	return unchanged
+}
+
+// saveApplyState stores the hashes of the slides applied with the current revision of the presentation.
+// The hashes of slides that were not applied are kept only if the presentation was at the revision
+// of the last apply before this one, and the number of slides is unchanged.
+func (d *Deck) saveApplyState(baseRevisionID string, pages []int, hashes []string) error {
+	if !d.incrementalApply || d.presentation.RevisionId == "" {
+		return nil
+	}
+	state := &applyState{
+		RevisionID:   d.presentation.RevisionId,
+		ManagedStart: d.managedStart,
+		Options:      d.optionsHash(),
+		Hashes:       make([]string, len(hashes)),
+	}
+	if prev, err := loadApplyState(d.profile, d.id); err == nil && prev != nil && prev.RevisionID == baseRevisionID &&
+		prev.ManagedStart == d.managedStart && prev.Options == state.Options && len(prev.Hashes) == len(hashes) {
+		copy(state.Hashes, prev.Hashes)
+	}
+	for _, page := range pages {
+		state.Hashes[page-1] = hashes[page-1]
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	p := applyStatePath(d.profile, d.id)
+	if err := mkdirState(filepath.Dir(p)); err != nil {
+		return fmt.Errorf("failed to create directory for the state of apply: %w", err)
+	}
+	if err := os.WriteFile(p, b, 0600); err != nil {
+		return fmt.Errorf("failed to write the state of apply: %w", err)
+	}
+	return nil
+}
+
+func loadApplyState(profile, id string) (*applyState, error) {
+	b, err := os.ReadFile(applyStatePath(profile, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read the state of apply: %w", err)
+	}
+	state := &applyState{}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, fmt.Errorf("failed to parse the state of apply: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,75 @@
+package deck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplySkipsUnchangedSlides(t *testing.T) {
+	dir := t.TempDir()
+	orig := applyStatePath
+	applyStatePath = func(profile, id string) string { return filepath.Join(dir, id+".json") }
+	t.Cleanup(func() { applyStatePath = orig })
+
+	newSlides := func(second string) Slides {
+		return Slides{
+			{Layout: "Title and Content", Titles: []string{"first"}},
+			{Layout: "Title and Content", Titles: []string{second}},
+		}
+	}
+	apply := func(t *testing.T, s *fakeServer, ss Slides, opts ...Option) *ApplyResult {
+		t.Helper()
+		// The fake server does not modify the presentation, so every slide differs from it.
+		result, err := newFakeDeck(t, s, append([]Option{WithIncrementalApply(true)}, opts...)...).Apply(t.Context(), ss)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	s := &fakeServer{presentation: newFakePresentation("a", "b")}
+	s.presentation.RevisionId = "rev-1"
+
+	if got := apply(t, s, newSlides("second")).Updated; got != 2 {
+		t.Errorf("first apply: got %d updated, want 2", got)
+	}
+	if got := apply(t, s, newSlides("second")).Updated; got != 0 {
+		t.Errorf("unchanged apply: got %d updated, want 0", got)
+	}
+	if got := apply(t, s, newSlides("changed")).Updated; got != 1 {
+		t.Errorf("apply with a changed slide: got %d updated, want 1", got)
+	}
+	if got := apply(t, s, newSlides("changed"), WithForceFullApply(true)).Updated; got != 2 {
+		t.Errorf("forced full apply: got %d updated, want 2", got)
+	}
+	if got := apply(t, s, newSlides("changed"), WithImageAnchor(ImageAnchorTop)).Updated; got != 2 {
+		t.Errorf("apply with changed options: got %d updated, want 2", got)
+	}
+	s.presentation.RevisionId = "rev-2"
+	if got := apply(t, s, newSlides("changed")).Updated; got != 2 {
+		t.Errorf("apply to a modified presentation: got %d updated, want 2", got)
+	}
+}
+
+func TestApplyWithoutIncrementalApply(t *testing.T) {
+	dir := t.TempDir()
+	orig := applyStatePath
+	applyStatePath = func(profile, id string) string { return filepath.Join(dir, id+".json") }
+	t.Cleanup(func() { applyStatePath = orig })
+
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	s.presentation.RevisionId = "rev-1"
+	ss := Slides{{Layout: "Title and Content", Titles: []string{"first"}}}
+	for range 2 {
+		result, err := newFakeDeck(t, s).Apply(t.Context(), ss)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Updated != 1 {
+			t.Errorf("got %d updated, want 1", result.Updated)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, s.presentation.PresentationId+".json")); !os.IsNotExist(err) {
+		t.Errorf("the state of apply was stored: %v", err)
+	}
+}
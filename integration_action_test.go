@@ -146,7 +146,7 @@ func TestAction(t *testing.T) {
 				}
 
 				// Apply the target slides using generateActions
-				if err := d.Apply(ctx, targetSlides); err != nil {
+				if _, err := d.Apply(ctx, targetSlides); err != nil {
 					t.Fatalf("failed to apply target slides: %v", err)
 				}
 			},
@@ -176,7 +176,7 @@ func TestAction(t *testing.T) {
 			if err := d.DeletePages(ctx, []int{0}); err != nil {
 				t.Fatal(err)
 			}
-			if err := d.Apply(ctx, tt.before); err != nil {
+			if _, err := d.Apply(ctx, tt.before); err != nil {
 				t.Fatal(err)
 			}
 			before, err := d.DumpSlides(ctx)
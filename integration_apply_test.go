@@ -38,7 +38,7 @@ func TestApply(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if err := d.Apply(ctx, tt.before); err != nil {
+			if _, err := d.Apply(ctx, tt.before); err != nil {
 				t.Fatal(err)
 			}
 			before, err := d.DumpSlides(ctx)
@@ -49,7 +49,7 @@ func TestApply(t *testing.T) {
 				t.Fatal(diff)
 			}
 
-			if err := d.Apply(ctx, tt.after); err != nil {
+			if _, err := d.Apply(ctx, tt.after); err != nil {
 				t.Fatal(err)
 			}
 			after, err := d.DumpSlides(ctx)
@@ -90,7 +90,7 @@ func TestApplyMarkdown(t *testing.T) {
 			if err := d.DeletePageAfter(ctx, 0); err != nil {
 				t.Fatal(err)
 			}
-			if err := d.Apply(ctx, fromMd); err != nil {
+			if _, err := d.Apply(ctx, fromMd); err != nil {
 				t.Fatal(err)
 			}
 			urls := d.ListSlideURLs()
@@ -223,7 +223,7 @@ func TestRoundTripSlidesToGoogleSlidesPresentationAndBack(t *testing.T) {
 			if err := d.DeletePageAfter(ctx, 0); err != nil {
 				t.Fatal(err)
 			}
-			if err := d.Apply(ctx, base); err != nil {
+			if _, err := d.Apply(ctx, base); err != nil {
 				t.Fatal(err)
 			}
 			applied, err := d.DumpSlides(ctx)
@@ -234,7 +234,7 @@ func TestRoundTripSlidesToGoogleSlidesPresentationAndBack(t *testing.T) {
 				diff := cmp.Diff(base, applied, cmpopts...)
 				t.Errorf("slides after apply do not match base: %s", diff)
 			}
-			if err := d.Apply(ctx, applied); err != nil {
+			if _, err := d.Apply(ctx, applied); err != nil {
 				t.Fatal(err)
 			}
 			applied2, err := d.DumpSlides(ctx)
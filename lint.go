@@ -0,0 +1,108 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+)
+
+// LintSeverity is the severity of a LintIssue.
+type LintSeverity string
+
+const (
+	// LintSeverityError is for issues that make apply fail.
+	LintSeverityError LintSeverity = "error"
+	// LintSeverityWarning is for issues that apply ignores, but are likely mistakes.
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// Limits of images that the Slides API accepts.
+const (
+	maxUploadImageBytes  = 50 << 20
+	maxUploadImagePixels = 25_000_000
+)
+
+// LintIssue is an issue of a slide reported by Lint.
+type LintIssue struct {
+	Index    int          `json:"index"` // index of the slide
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("slide %d: %s: %s", i.Index+1, i.Severity, i.Message)
+}
+
+// Lint reports the issues of ss that applying them to the presentation of d would run into:
+// unknown layouts and images that cannot be uploaded as errors, and unknown style names as warnings.
+// It does not modify the presentation, and works in memory, so it reflects the layouts and styles of the last refresh.
+func Lint(ss Slides, d *Deck) []LintIssue {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var issues []LintIssue
+	report := func(i int, severity LintSeverity, format string, a ...any) {
+		issues = append(issues, LintIssue{Index: i, Severity: severity, Message: fmt.Sprintf(format, a...)})
+	}
+	layoutMap := d.layoutMap()
+	for i, slide := range ss {
+		if slide == nil {
+			continue
+		}
+		layout := d.layoutOrDefault(i, slide.Layout)
+		if _, ok := layoutMap[layout]; !ok {
+			report(i, LintSeverityError, "%s", newLayoutNotFoundError([]string{layout}, layoutMap))
+		}
+		reported := map[string]struct{}{}
+		for _, f := range slideFragments(slide) {
+			if f.StyleName == "" || d.knownStyle(f.StyleName) {
+				continue
+			}
+			if _, ok := reported[f.StyleName]; ok {
+				continue
+			}
+			reported[f.StyleName] = struct{}{}
+			report(i, LintSeverityWarning, "style not found: %q", f.StyleName)
+		}
+		for _, img := range slide.Images {
+			if msg := d.lintImage(img); msg != "" {
+				report(i, LintSeverityError, "%s", msg)
+			}
+		}
+	}
+	return issues
+}
+
+// knownStyle reports whether the style name is defined in the "style" layout or is a default style.
+func (d *Deck) knownStyle(name string) bool {
+	if _, ok := d.styles[name]; ok {
+		return true
+	}
+	_, ok := defaultStyles[name]
+	return ok
+}
+
+// lintImage returns a message describing why the image cannot be uploaded, or an empty string if it can.
+func (d *Deck) lintImage(img *Image) string {
+	src := img.url
+	if src == "" {
+		src = "code block"
+	}
+	if len(img.Bytes()) == 0 {
+		return fmt.Sprintf("image could not be fetched: %s", src)
+	}
+	data, _, err := d.imageUploadData(img)
+	if err != nil {
+		return err.Error()
+	}
+	if len(data) > maxUploadImageBytes {
+		return fmt.Sprintf("image from %s is %d bytes, larger than the limit of %d bytes", src, len(data), maxUploadImageBytes)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Sprintf("failed to decode image from %s: %v", src, err)
+	}
+	if pixels := cfg.Width * cfg.Height; pixels > maxUploadImagePixels {
+		return fmt.Sprintf("image from %s is %dx%d, larger than the limit of %d pixels", src, cfg.Width, cfg.Height, maxUploadImagePixels)
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package deck
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestLint(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 5001, 5000))); err != nil {
+		t.Fatal(err)
+	}
+	large := &Image{b: buf.Bytes(), mimeType: MIMETypeImagePNG, url: "https://example.com/large.png"}
+	ok, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &Deck{
+		presentation:       newFakePresentation("a"),
+		defaultTitleLayout: "Title and Content",
+		defaultLayout:      "Title and Content",
+		styles:             map[string]*slides.TextStyle{"red": {}},
+	}
+	ss := Slides{
+		{
+			Bodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{
+				{Value: "a", StyleName: "red"},
+				{Value: "b", StyleName: "bold"},
+				{Value: "c", StyleName: "blue"},
+				{Value: "d", StyleName: "blue"},
+			}}}}},
+			Images: []*Image{ok},
+		},
+		{
+			Layout: "Missing",
+			Images: []*Image{{url: "https://example.com/missing.png"}, large},
+		},
+	}
+	got := Lint(ss, d)
+	want := []LintIssue{
+		{Index: 0, Severity: LintSeverityWarning, Message: `style not found: "blue"`},
+		{Index: 1, Severity: LintSeverityError, Message: `layout not found: ["Missing"]` + "\navailable layouts: [Title Slide Title and Content]"},
+		{Index: 1, Severity: LintSeverityError, Message: "image could not be fetched: https://example.com/missing.png"},
+		{Index: 1, Severity: LintSeverityError, Message: "image from https://example.com/large.png is 5001x5000, larger than the limit of 25000000 pixels"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
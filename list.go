@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/k1LoW/errors"
+	"google.golang.org/api/drive/v3"
 )
 
 // List Google Slides presentations.
@@ -24,6 +25,8 @@ func (d *Deck) List() (_ []*Presentation, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	var presentations []*Presentation
 
 	r, err := d.driveSrv.Files.List().SupportsAllDrives(true).IncludeItemsFromAllDrives(true).
@@ -42,8 +45,49 @@ func (d *Deck) List() (_ []*Presentation, err error) {
 	return presentations, nil
 }
 
+// ListPresentations lists Google Slides presentations in the folder of the Deck.
+// If the folder is not set, it lists all accessible presentations.
+func (d *Deck) ListPresentations(ctx context.Context) (_ []*Presentation, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	q := "mimeType = 'application/vnd.google-apps.presentation' and trashed = false"
+	if d.folderID != "" {
+		q += fmt.Sprintf(" and '%s' in parents", d.folderID)
+	}
+	var (
+		presentations []*Presentation
+		pageToken     string
+	)
+	for {
+		var r *drive.FileList
+		if err := d.call(ctx, "list presentations", func(ctx context.Context) (err error) {
+			r, err = d.driveSrv.Files.List().SupportsAllDrives(true).IncludeItemsFromAllDrives(true).
+				Q(q).Fields("nextPageToken", "files(id, name)").PageToken(pageToken).Context(ctx).Do()
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list presentations: %w", err)
+		}
+		for _, f := range r.Files {
+			presentations = append(presentations, &Presentation{
+				ID:    f.Id,
+				Title: f.Name,
+			})
+		}
+		if r.NextPageToken == "" {
+			break
+		}
+		pageToken = r.NextPageToken
+	}
+	return presentations, nil
+}
+
 // ListLayouts lists layouts of the presentation.
 func (d *Deck) ListLayouts() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	var layouts []string
 	for _, l := range d.presentation.Layouts {
 		layouts = append(layouts, l.LayoutProperties.DisplayName)
@@ -53,6 +97,8 @@ func (d *Deck) ListLayouts() []string {
 
 // ListSlideURLs lists URLs of the slides in the Google Slides presentation.
 func (d *Deck) ListSlideURLs() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	var slideURLs []string
 	baseURL := PresentationIDtoURL(d.id)
 	for _, s := range d.presentation.Slides {
@@ -0,0 +1,46 @@
+package deck
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/drive/v3"
+)
+
+func TestListPresentations(t *testing.T) {
+	var queries []string
+	s := &fakeServer{
+		presentation: newFakePresentation(),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.Query().Get("q"))
+			list := &drive.FileList{}
+			switch r.URL.Query().Get("pageToken") {
+			case "":
+				list.Files = []*drive.File{{Id: "p1", Name: "one"}, {Id: "p2", Name: "two"}}
+				list.NextPageToken = "next"
+			case "next":
+				list.Files = []*drive.File{{Id: "p3", Name: "three"}}
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		},
+	}
+	d := newFakeDeck(t, s, WithFolderID("folder"))
+
+	got, err := d.ListPresentations(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Presentation{{ID: "p1", Title: "one"}, {ID: "p2", Title: "two"}, {ID: "p3", Title: "three"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("presentations mismatch (-want +got):\n%s", diff)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("got %d requests, want 2", len(queries))
+	}
+	if !strings.Contains(queries[0], "'folder' in parents") {
+		t.Errorf("query should be limited to the folder: %s", queries[0])
+	}
+}
@@ -2,7 +2,6 @@ package md
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/google/cel-go/cel"
 )
@@ -70,7 +69,7 @@ func (md *MD) reflectDefaults() error {
 				"images":          content.Images,
 				"comments":        content.Comments,
 				"headings":        content.Headings,
-				"speakerNote":     strings.Join(content.Comments, "\n\n"),
+				"speakerNote":     content.speakerNote(),
 				"topHeadingLevel": topHeadingLevel,
 			})
 			if err != nil {
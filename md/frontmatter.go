@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/goccy/go-yaml"
 	"github.com/k1LoW/deck/config"
@@ -83,6 +85,64 @@ func ApplyFrontmatterToMD(mdFile, title, presentationID string) (err error) {
 	return nil
 }
 
+// ReadFrontmatter reads the frontmatter of the markdown file.
+// It returns an empty Frontmatter if the file has no frontmatter.
+func ReadFrontmatter(mdFile string) (_ *Frontmatter, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	b, err := os.ReadFile(mdFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	fm, _ := splitFrontmatter(b)
+	if fm == nil {
+		return &Frontmatter{}, nil
+	}
+	return fm, nil
+}
+
+// splitFrontmatter splits b into the YAML frontmatter and the rest.
+// It returns nil frontmatter and b as is if b does not start with a valid frontmatter.
+func splitFrontmatter(b []byte) (*Frontmatter, []byte) {
+	sep := []byte("---\n")
+	if !bytes.HasPrefix(b, sep) {
+		return nil, b
+	}
+	stuff := bytes.SplitN(bytes.TrimPrefix(b, sep), sep, 2)
+	if len(stuff) != 2 {
+		return nil, b
+	}
+	fm := &Frontmatter{}
+	if err := yaml.Unmarshal(stuff[0], fm); err != nil {
+		return nil, b
+	}
+	var keys map[string]any
+	if err := yaml.Unmarshal(stuff[0], &keys); err == nil {
+		known := frontmatterKeys()
+		for k := range keys {
+			if !slices.Contains(known, k) {
+				fm.UnknownKeys = append(fm.UnknownKeys, k)
+			}
+		}
+		slices.Sort(fm.UnknownKeys)
+	}
+	return fm, stuff[1]
+}
+
+// frontmatterKeys returns the keys of the fields of Frontmatter.
+func frontmatterKeys() []string {
+	var keys []string
+	t := reflect.TypeFor[Frontmatter]()
+	for i := range t.NumField() {
+		if k, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); k != "" && k != "-" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
 func (fm *Frontmatter) applyConfig(cfg *config.Config) *Frontmatter {
 	if cfg == nil || reflect.DeepEqual(*cfg, config.Config{}) {
 		return fm
@@ -93,6 +153,12 @@ func (fm *Frontmatter) applyConfig(cfg *config.Config) *Frontmatter {
 	if fm.Breaks == nil {
 		fm.Breaks = cfg.Breaks
 	}
+	if fm.PreserveBlankLines == nil {
+		fm.PreserveBlankLines = cfg.PreserveBlankLines
+	}
+	if fm.MergeTableCells == nil {
+		fm.MergeTableCells = cfg.MergeTableCells
+	}
 	if fm.CodeBlockToImageCommand == "" {
 		fm.CodeBlockToImageCommand = cfg.CodeBlockToImageCommand
 	}
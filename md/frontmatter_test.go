@@ -387,3 +387,61 @@ func TestApplyConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestFrontmatterFields(t *testing.T) {
+	markdown := `---
+title: Test Title
+folderID: folder
+basePresentationID: base
+defaultLayout: Title and Content
+defaultTitleLayout: Title Slide
+author: Test Author
+tags:
+  - tag1
+---
+# Slide Title`
+	m, err := Parse(".", []byte(markdown), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Frontmatter{
+		Title:              "Test Title",
+		FolderID:           "folder",
+		BasePresentationID: "base",
+		DefaultLayout:      "Title and Content",
+		DefaultTitleLayout: "Title Slide",
+		UnknownKeys:        []string{"author", "tags"},
+	}
+	if diff := cmp.Diff(want, m.Frontmatter); diff != "" {
+		t.Error(diff)
+	}
+	if len(m.Contents) != 1 {
+		t.Errorf("got %d contents, want 1", len(m.Contents))
+	}
+
+	p := filepath.Join(t.TempDir(), "deck.md")
+	if err := os.WriteFile(p, []byte(strings.ReplaceAll(markdown, "\n", "\r\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fm, err := ReadFrontmatter(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, fm); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("no frontmatter", func(t *testing.T) {
+		p := filepath.Join(t.TempDir(), "deck.md")
+		if err := os.WriteFile(p, []byte("# Slide Title\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		fm, err := ReadFrontmatter(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(&Frontmatter{}, fm); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package md
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/deck/template"
+	"github.com/k1LoW/errors"
+)
+
+// Issue is a generic issue of an issue tracker.
+type Issue struct {
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
+	URL      string `json:"url"`
+}
+
+// SlidesFromIssues generates a slide for each issue by expanding the markdown template.
+// The template can refer to the issue by the variables title, status, assignee and url, e.g. "# {{title}}".
+func SlidesFromIssues(issues []Issue, template string) (_ deck.Slides, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	contents, err := issueContents(issues, template)
+	if err != nil {
+		return nil, err
+	}
+	return contents.toSlides(context.Background(), "")
+}
+
+// SlidesFromIssuesGroupedByStatus is like SlidesFromIssues, but groups the issues by status
+// in order of first appearance, and starts each group with a section slide titled by the status.
+func SlidesFromIssuesGroupedByStatus(issues []Issue, template string) (_ deck.Slides, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	var statuses []string
+	groups := map[string][]Issue{}
+	for _, issue := range issues {
+		if _, ok := groups[issue.Status]; !ok {
+			statuses = append(statuses, issue.Status)
+		}
+		groups[issue.Status] = append(groups[issue.Status], issue)
+	}
+	var contents Contents
+	for _, status := range statuses {
+		section, err := ParseContent(".", fmt.Appendf(nil, "# %s\n", status), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse section for status %q: %w", status, err)
+		}
+		contents = append(contents, section)
+		cs, err := issueContents(groups[status], template)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, cs...)
+	}
+	return contents.toSlides(context.Background(), "")
+}
+
+func issueContents(issues []Issue, tmpl string) (Contents, error) {
+	var contents Contents
+	for i, issue := range issues {
+		expanded, err := template.Expand(tmpl, map[string]any{
+			"title":    issue.Title,
+			"status":   issue.Status,
+			"assignee": issue.Assignee,
+			"url":      issue.URL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand template for issue %d: %w", i, err)
+		}
+		c, err := ParseContent(".", []byte(expanded), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse slide for issue %d: %w", i, err)
+		}
+		contents = append(contents, c)
+	}
+	return contents, nil
+}
@@ -0,0 +1,46 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSlidesFromIssuesGroupedByStatus(t *testing.T) {
+	issues := []Issue{
+		{Title: "Fix login", Status: "Done", Assignee: "alice", URL: "https://example.com/1"},
+		{Title: "Add export", Status: "In Progress", Assignee: "bob", URL: "https://example.com/2"},
+		{Title: "Update docs", Status: "Done", Assignee: "carol", URL: "https://example.com/3"},
+	}
+	tmpl := "# {{title}}\n\n- Assignee: {{assignee}}\n- {{url}}\n"
+
+	ss, err := SlidesFromIssuesGroupedByStatus(issues, tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]string
+	for _, s := range ss {
+		got = append(got, s.Titles)
+	}
+	want := [][]string{
+		{"Done"},
+		{"Fix login"},
+		{"Update docs"},
+		{"In Progress"},
+		{"Add export"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("titles mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := ss[1].Bodies[0].String(), "- Assignee: alice\n- https://example.com/1\n"; got != want {
+		t.Errorf("got body %q, want %q", got, want)
+	}
+
+	ss, err = SlidesFromIssues(issues, tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss) != len(issues) {
+		t.Errorf("got %d slides, want %d", len(ss), len(issues))
+	}
+}
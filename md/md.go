@@ -14,7 +14,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/goccy/go-yaml"
 	"github.com/k1LoW/deck"
 	"github.com/k1LoW/deck/config"
 	"github.com/k1LoW/deck/template"
@@ -62,10 +61,24 @@ type Frontmatter struct {
 	Title          string `yaml:"title,omitempty" json:"title,omitempty"`                   // title of the presentation
 	// Whether to display line breaks in the document as line breaks
 	Breaks *bool `yaml:"breaks,omitempty" json:"breaks,omitempty"`
+	// Whether to preserve consecutive blank lines within a slide as empty paragraphs
+	PreserveBlankLines *bool `yaml:"preserveBlankLines,omitempty" json:"preserveBlankLines,omitempty"`
+	// Whether to merge table cells consisting of only "<" or "^" into the cell on the left or above
+	MergeTableCells *bool `yaml:"mergeTableCells,omitempty" json:"mergeTableCells,omitempty"`
 	// Conditions for default
 	Defaults []DefaultCondition `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 	// command to convert code blocks to images
 	CodeBlockToImageCommand string `yaml:"codeBlockToImageCommand,omitempty" json:"codeBlockToImageCommand,omitempty"`
+	// folder ID to create the presentation and upload temporary images to
+	FolderID string `yaml:"folderID,omitempty" json:"folderID,omitempty"`
+	// base presentation ID whose theme is used when creating the presentation
+	BasePresentationID string `yaml:"basePresentationID,omitempty" json:"basePresentationID,omitempty"`
+	// layout of pages without a layout
+	DefaultLayout string `yaml:"defaultLayout,omitempty" json:"defaultLayout,omitempty"`
+	// layout of the first page without a layout
+	DefaultTitleLayout string `yaml:"defaultTitleLayout,omitempty" json:"defaultTitleLayout,omitempty"`
+	// sorted keys of the frontmatter that deck does not know, which are ignored
+	UnknownKeys []string `yaml:"-" json:"-"`
 }
 
 type DefaultCondition struct {
@@ -85,6 +98,10 @@ type Config struct {
 	Freeze *bool  `json:"freeze,omitempty"` // freeze the page
 	Ignore *bool  `json:"ignore,omitempty"` // ignore the page (skip slide generation)
 	Skip   *bool  `json:"skip,omitempty"`   // skip the page (do not show in the presentation)
+
+	Accent string `json:"accent,omitempty"` // theme color (ACCENT1-6) of the title and rules
+
+	Table *deck.TableStyleOverride `json:"table,omitempty"` // style of the following table
 }
 
 type CodeBlock struct {
@@ -98,6 +115,7 @@ type Content struct {
 	Freeze         *bool              `json:"freeze,omitempty"`
 	Ignore         *bool              `json:"ignore,omitempty"`
 	Skip           *bool              `json:"skip,omitempty"`
+	Accent         string             `json:"accent,omitempty"`
 	Titles         []string           `json:"titles,omitempty"`
 	TitleBodies    []*deck.Body       `json:"-"`
 	Subtitles      []string           `json:"subtitles,omitempty"`
@@ -108,9 +126,58 @@ type Content struct {
 	BlockQuotes    []*deck.BlockQuote `json:"block_quotes,omitempty"`
 	Tables         []*deck.Table      `json:"tables,omitempty"`
 	Comments       []string           `json:"comments,omitempty"`
+	Note           string             `json:"note,omitempty"`
 	Headings       map[int][]string   `json:"headings,omitempty"`
 }
 
+// parseImageAttributes parses the attributes following an image, e.g. `{width=300 height=50%}`.
+// It returns the number of bytes of the attributes, or 0 if b does not start with attributes of an image.
+func parseImageAttributes(b []byte) (width, height string, n int) {
+	if !bytes.HasPrefix(b, []byte("{")) {
+		return "", "", 0
+	}
+	end := bytes.IndexByte(b, '}')
+	if end < 0 {
+		return "", "", 0
+	}
+	for _, attr := range strings.Fields(string(b[1:end])) {
+		k, v, ok := strings.Cut(attr, "=")
+		if !ok {
+			return "", "", 0
+		}
+		v = strings.Trim(v, `"'`)
+		switch k {
+		case "width":
+			width = v
+		case "height":
+			height = v
+		default:
+			return "", "", 0
+		}
+	}
+	if width == "" && height == "" {
+		return "", "", 0
+	}
+	return width, height, end + 1
+}
+
+// imageSlotRe matches the directive in the title of an image to place the image in the named image placeholder.
+// e.g. ![alt](image.png "image-slot: left")
+var imageSlotRe = regexp.MustCompile(`^\s*image-slot:\s*(\S+)\s*$`)
+
+// noteMarker is the line that starts speaker notes. The rest of the page after it is notes.
+// It must stand alone on its line, so ordinary paragraphs starting with "Note:" stay in the body.
+const noteMarker = "Note:"
+
+// speakerNote returns the speaker note of the content from comments and the notes after the note marker.
+func (c *Content) speakerNote() string {
+	notes := slices.Clone(c.Comments)
+	if c.Note != "" {
+		notes = append(notes, c.Note)
+	}
+	return strings.Join(notes, "\n\n")
+}
+
 // ParseFile parses a markdown file into contents.
 func ParseFile(f string, cfg *config.Config) (_ *MD, err error) {
 	defer func() {
@@ -145,30 +212,24 @@ func Parse(baseDir string, b []byte, cfg *config.Config) (_ *MD, err error) {
 	sep := []byte("---\n")
 
 	// Extract YAML frontmatter if present
-	var frontmatter *Frontmatter
-	mayHaveFrontmatter := bytes.HasPrefix(b, sep)
-	if mayHaveFrontmatter {
-		stuff := bytes.SplitN(bytes.TrimPrefix(b, sep), sep, 2)
-		if len(stuff) == 2 {
-			frontmatter = &Frontmatter{}
-			if err := yaml.Unmarshal(stuff[0], frontmatter); err == nil {
-				b = stuff[1]
-			} else {
-				frontmatter = nil
-			}
-		}
-	}
+	frontmatter, b := splitFrontmatter(b)
 	frontmatter = frontmatter.applyConfig(cfg)
 
 	bpages := splitPages(bytes.TrimPrefix(b, sep))
-	var breaks bool
+	var breaks, preserveBlankLines, mergeTableCells bool
 	if frontmatter != nil && frontmatter.Breaks != nil {
 		breaks = *frontmatter.Breaks
 	}
+	if frontmatter != nil && frontmatter.PreserveBlankLines != nil {
+		preserveBlankLines = *frontmatter.PreserveBlankLines
+	}
+	if frontmatter != nil && frontmatter.MergeTableCells != nil {
+		mergeTableCells = *frontmatter.MergeTableCells
+	}
 
 	var contents Contents
 	for _, bpage := range bpages {
-		c, err := ParseContent(baseDir, bpage, breaks)
+		c, err := parseContent(baseDir, bpage, breaks, preserveBlankLines, mergeTableCells)
 		if err != nil {
 			return nil, err
 		}
@@ -187,11 +248,18 @@ func Parse(baseDir string, b []byte, cfg *config.Config) (_ *MD, err error) {
 
 // ParseContent parses a single markdown content into a Content structure.
 // It processes headings, lists, paragraphs, and HTML blocks to create a structured representation.
+// Consecutive blank lines between blocks collapse to a single paragraph break.
 func ParseContent(baseDir string, b []byte, breaks bool) (_ *Content, err error) {
 	defer func() {
 		err = errors.WithStack(err)
 	}()
+	return parseContent(baseDir, b, breaks, false, false)
+}
 
+// parseContent is like ParseContent, but if preserveBlankLines is true,
+// each extra blank line between blocks becomes an empty paragraph,
+// and if mergeTableCells is true, the merge markers of table cells are parsed.
+func parseContent(baseDir string, b []byte, breaks, preserveBlankLines, mergeTableCells bool) (*Content, error) {
 	// Parse once and reuse the AST
 	md := newParser()
 	reader := text.NewReader(b)
@@ -224,7 +292,7 @@ func ParseContent(baseDir string, b []byte, breaks bool) (_ *Content, err error)
 	content := &Content{
 		Headings: make(map[int][]string),
 	}
-	if err := walkContents(doc, baseDir, b, content, titleLevel, breaks); err != nil {
+	if err := walkContents(doc, baseDir, b, content, titleLevel, breaks, preserveBlankLines, mergeTableCells); err != nil {
 		return nil, fmt.Errorf("failed to walk body: %w", err)
 	}
 
@@ -309,7 +377,8 @@ func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd strin
 			Images:         images,
 			BlockQuotes:    content.BlockQuotes,
 			Tables:         content.Tables,
-			SpeakerNote:    strings.Join(content.Comments, "\n\n"),
+			SpeakerNote:    content.speakerNote(),
+			Accent:         content.Accent,
 		}
 		if content.Freeze != nil {
 			slide.Freeze = *content.Freeze
@@ -322,12 +391,29 @@ func (contents Contents) toSlides(ctx context.Context, codeBlockToImageCmd strin
 	return slides, nil
 }
 
-func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titleLevel int, breaks bool) error {
+func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titleLevel int, breaks, preserveBlankLines, mergeTableCells bool) error {
 	if len(content.Bodies) == 0 {
 		content.Bodies = append(content.Bodies, &deck.Body{})
 	}
 	currentBody := content.Bodies[len(content.Bodies)-1]
 	currentListMarker := deck.BulletNone
+	// listMarkers is the stack of the markers of the lists being walked, to restore the marker after a nested list.
+	var listMarkers []deck.Bullet
+	// tableStyle is the style for the next table given by a table config comment.
+	var tableStyle *deck.TableStyleOverride
+	// appendBlankParagraphs appends an empty paragraph for each extra blank line before the block.
+	appendBlankParagraphs := func(n ast.Node) {
+		if !preserveBlankLines || len(currentBody.Paragraphs) == 0 {
+			return
+		}
+		for range extraBlankLinesBefore(n, b) {
+			currentBody.Paragraphs = append(currentBody.Paragraphs, &deck.Paragraph{
+				Fragments: []*deck.Fragment{},
+				Bullet:    deck.BulletNone,
+				Nesting:   0,
+			})
+		}
+	}
 	if err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if entering {
 			switch v := n.(type) {
@@ -376,6 +462,7 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 						content.Bodies = append(content.Bodies, currentBody)
 					}
 				default:
+					appendBlankParagraphs(v)
 					currentBody.Paragraphs = append(currentBody.Paragraphs, &deck.Paragraph{
 						Fragments: deckFrags,
 						Bullet:    deck.BulletNone,
@@ -388,7 +475,9 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 					content.Bodies = append(content.Bodies, currentBody)
 				}
 			case *ast.List:
+				appendBlankParagraphs(v)
 				currentListMarker = toBullet(v.Marker)
+				listMarkers = append(listMarkers, currentListMarker)
 			case *ast.ListItem:
 				tb := v.FirstChild()
 				frags, images, err := toFragments(baseDir, b, tb, deck.Fragment{})
@@ -430,6 +519,14 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 				if v.Parent() != nil && v.Parent().Kind() == ast.KindListItem {
 					return ast.WalkSkipChildren, nil
 				}
+				// The rest of the page after the note marker is speaker notes
+				if start, ok := firstSegmentStart(v); ok && v.Parent() != nil && v.Parent().Kind() == ast.KindDocument {
+					line, rest, _ := bytes.Cut(b[start:], []byte("\n"))
+					if string(bytes.TrimSpace(line)) == noteMarker {
+						content.Note = strings.TrimSpace(string(rest))
+						return ast.WalkStop, nil
+					}
+				}
 				frags, images, err := toFragments(baseDir, b, v, deck.Fragment{})
 				if err != nil {
 					return ast.WalkStop, err
@@ -438,6 +535,7 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 				if len(frags) == 0 {
 					return ast.WalkContinue, nil
 				}
+				appendBlankParagraphs(v)
 				currentBody.Paragraphs = append(currentBody.Paragraphs, &deck.Paragraph{
 					Fragments: toDeckFragments(frags, breaks),
 					Bullet:    deck.BulletNone,
@@ -449,10 +547,15 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 						strings.TrimPrefix(strings.TrimSpace(string(v.Lines().Value(b))), "<!--"), "-->"))
 					config := &Config{}
 					if err := json.Unmarshal([]byte(block), config); err == nil {
+						if config.Table != nil {
+							tableStyle = config.Table
+							return ast.WalkContinue, nil
+						}
 						content.Layout = config.Layout
 						content.Freeze = config.Freeze
 						content.Ignore = config.Ignore
 						content.Skip = config.Skip
+						content.Accent = config.Accent
 						return ast.WalkContinue, nil
 					}
 					content.Comments = append(content.Comments, block)
@@ -464,6 +567,7 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 					if trimmed == "<br>" || trimmed == "<br/>" || trimmed == "<br />" {
 						trimmed = "\n"
 					}
+					appendBlankParagraphs(v)
 					currentBody.Paragraphs = append(currentBody.Paragraphs, &deck.Paragraph{
 						Fragments: []*deck.Fragment{{
 							Value: trimmed,
@@ -486,10 +590,12 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 					Content:  string(c),
 				})
 			case *east.Table:
-				table, err := parseTable(v, baseDir, b, breaks)
+				table, err := parseTable(v, baseDir, b, breaks, mergeTableCells)
 				if err != nil {
 					return ast.WalkStop, err
 				}
+				table.Style = tableStyle
+				tableStyle = nil
 				content.Tables = append(content.Tables, table)
 				return ast.WalkSkipChildren, nil
 			case *ast.Blockquote:
@@ -497,7 +603,7 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 					Headings: make(map[int][]string),
 				}
 				for v := n.FirstChild(); v != nil; v = v.NextSibling() {
-					if err := walkContents(v, baseDir, b, blockQuoteContent, 1, breaks); err != nil {
+					if err := walkContents(v, baseDir, b, blockQuoteContent, 1, breaks, preserveBlankLines, mergeTableCells); err != nil {
 						return ast.WalkStop, err
 					}
 				}
@@ -518,6 +624,12 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 				}
 				return ast.WalkSkipChildren, nil
 			}
+		} else if _, ok := n.(*ast.List); ok && len(listMarkers) > 0 {
+			listMarkers = listMarkers[:len(listMarkers)-1]
+			currentListMarker = deck.BulletNone
+			if len(listMarkers) > 0 {
+				currentListMarker = listMarkers[len(listMarkers)-1]
+			}
 		}
 		return ast.WalkContinue, nil
 	}); err != nil {
@@ -526,6 +638,69 @@ func walkContents(doc ast.Node, baseDir string, b []byte, content *Content, titl
 	return nil
 }
 
+// extraBlankLinesBefore returns the number of blank lines between the top-level block and the previous
+// paragraph, list, heading, or HTML block beyond the single blank line that separates blocks.
+func extraBlankLinesBefore(n ast.Node, b []byte) int {
+	if n.Parent() == nil || n.Parent().Kind() != ast.KindDocument {
+		return 0
+	}
+	prev := n.PreviousSibling()
+	if prev == nil {
+		return 0
+	}
+	switch v := prev.(type) {
+	case *ast.Paragraph, *ast.List, *ast.Heading:
+	case *ast.HTMLBlock:
+		if v.HTMLBlockType == ast.HTMLBlockType2 {
+			return 0
+		}
+	default:
+		return 0
+	}
+	start, ok := firstSegmentStart(n)
+	if !ok {
+		return 0
+	}
+	stop, ok := lastSegmentStop(prev)
+	if !ok || stop <= 0 || stop > start {
+		return 0
+	}
+	blank := bytes.Count(b[:start], []byte("\n")) - bytes.Count(b[:stop-1], []byte("\n")) - 1
+	return max(0, blank-1)
+}
+
+// firstSegmentStart returns the start offset of the first line of the block or its descendant blocks.
+func firstSegmentStart(n ast.Node) (int, bool) {
+	if n.Type() != ast.TypeBlock {
+		return 0, false
+	}
+	if lines := n.Lines(); lines != nil && lines.Len() > 0 {
+		return lines.At(0).Start, true
+	}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if start, ok := firstSegmentStart(c); ok {
+			return start, true
+		}
+	}
+	return 0, false
+}
+
+// lastSegmentStop returns the stop offset of the last line of the block or its descendant blocks.
+func lastSegmentStop(n ast.Node) (int, bool) {
+	if n.Type() != ast.TypeBlock {
+		return 0, false
+	}
+	if lines := n.Lines(); lines != nil && lines.Len() > 0 {
+		return lines.At(lines.Len() - 1).Stop, true
+	}
+	for c := n.LastChild(); c != nil; c = c.PreviousSibling() {
+		if stop, ok := lastSegmentStop(c); ok {
+			return stop, true
+		}
+	}
+	return 0, false
+}
+
 var standaloneCommandReg = regexp.MustCompile(`^[-_.+a-zA-Z0-9]+$`)
 
 func buildCommand(c string) (string, []string, error) {
@@ -743,6 +918,19 @@ func toFragments(baseDir string, b []byte, n ast.Node, seedFragment deck.Fragmen
 			if err != nil {
 				return nil, nil, err
 			}
+			if m := imageSlotRe.FindSubmatch(childNode.Title); m != nil {
+				image.SetSlot(string(m[1]))
+			}
+			if next, ok := childNode.NextSibling().(*ast.Text); ok {
+				if width, height, n := parseImageAttributes(next.Segment.Value(b)); n > 0 {
+					if err := image.SetSize(width, height); err != nil {
+						return nil, nil, fmt.Errorf("invalid size of image %s: %w", imageLink, err)
+					}
+					// Remove the attributes from the text following the image.
+					next.Segment = next.Segment.WithStart(next.Segment.Start + n)
+				}
+			}
+			image.SetAlt(altText(childNode, b))
 			images = append(images, image)
 		case *ast.RawHTML:
 			// Get the raw HTML content
@@ -894,7 +1082,8 @@ func contentEqual(old, new *Content) bool {
 	}
 
 	// Compare layout and flags
-	if old.Layout != new.Layout || old.Freeze != new.Freeze || old.Skip != new.Skip || old.Ignore != new.Ignore {
+	if old.Layout != new.Layout || old.Freeze != new.Freeze || old.Skip != new.Skip || old.Ignore != new.Ignore ||
+		old.Accent != new.Accent {
 		return false
 	}
 
@@ -909,7 +1098,7 @@ func contentEqual(old, new *Content) bool {
 	}
 
 	// Compare comments
-	if !slices.Equal(old.Comments, new.Comments) {
+	if !slices.Equal(old.Comments, new.Comments) || old.Note != new.Note {
 		return false
 	}
 
@@ -962,6 +1151,18 @@ func contentEqual(old, new *Content) bool {
 	return true
 }
 
+// altText returns the plain text of the alt text of the image.
+func altText(n *ast.Image, b []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := n.(*ast.Text); ok && entering {
+			sb.Write(t.Segment.Value(b))
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
 // toBullet converts a marker byte to a Bullet type.
 func toBullet(m byte) deck.Bullet {
 	switch m {
@@ -7,8 +7,11 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/deck"
 	"github.com/tenntenn/golden"
 )
 
@@ -433,3 +436,264 @@ func TestCRLFSupport(t *testing.T) {
 		t.Errorf("ParseFile with CRLF and Parse with LF produce different results.\nLF Parse result:\n%s\n\nCRLF ParseFile result:\n%s", string(lfJSON), string(crlfFromFileJSON))
 	}
 }
+
+func TestParseBlankLines(t *testing.T) {
+	body := "# Title\n\na\n\n\n\nb\n\n- c\n\n\nd\n\n\n---\n\n\n# Next\n\ne\n"
+	tests := []struct {
+		name        string
+		frontmatter string
+		want        []string
+	}{
+		{"collapsed by default", "", []string{"a", "b", "- c", "d"}},
+		{"preserved", "---\npreserveBlankLines: true\n---\n", []string{"a", "", "", "b", "- c", "", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := Parse(".", []byte(tt.frontmatter+body), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(md.Contents) != 2 {
+				t.Fatalf("got %d contents, want 2", len(md.Contents))
+			}
+			var got []string
+			for _, p := range md.Contents[0].Bodies[0].Paragraphs {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			// Blank lines around the slide separator are not part of any slide.
+			if got := len(md.Contents[1].Bodies[0].Paragraphs); got != 1 {
+				t.Errorf("got %d paragraphs in the next slide, want 1", got)
+			}
+		})
+	}
+}
+
+func TestParseNoteMarker(t *testing.T) {
+	b := []byte("# Title\n\nbody\n\n<!-- comment -->\n\nNote:\nfirst note\n\n- second note\n\n---\n\n# Next\n\nNote: not a note\n")
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(md.Contents) != 2 {
+		t.Fatalf("got %d contents, want 2", len(md.Contents))
+	}
+	c := md.Contents[0]
+	if want := "first note\n\n- second note"; c.Note != want {
+		t.Errorf("got note %q, want %q", c.Note, want)
+	}
+	if got := len(c.Bodies[0].Paragraphs); got != 1 {
+		t.Errorf("got %d paragraphs, want 1; notes should not be in the body", got)
+	}
+	slides, err := md.ToSlides(t.Context(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "comment\n\nfirst note\n\n- second note"; slides[0].SpeakerNote != want {
+		t.Errorf("got speaker note %q, want %q", slides[0].SpeakerNote, want)
+	}
+	if md.Contents[1].Note != "" {
+		t.Errorf("got note %q, want none; inline Note: is body text", md.Contents[1].Note)
+	}
+	if got := len(md.Contents[1].Bodies[0].Paragraphs); got != 1 {
+		t.Errorf("got %d paragraphs, want 1", got)
+	}
+}
+
+func TestParseImageSlot(t *testing.T) {
+	b := []byte("# Title\n\n![a](../testdata/test.png \"image-slot: left\")\n\n![b](../testdata/test.jpeg)\n")
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := md.Contents[0].Images
+	if len(images) != 2 {
+		t.Fatalf("got %d images, want 2", len(images))
+	}
+	if got := images[0].Slot(); got != "left" {
+		t.Errorf("got slot %q, want %q", got, "left")
+	}
+	if got := images[1].Slot(); got != "" {
+		t.Errorf("got slot %q, want none", got)
+	}
+}
+
+func TestSplitSections(t *testing.T) {
+	b := []byte("# Intro\n\n<!-- section -->\n# Part 1\n\n---\n\n# Part 1 cont.\n\n<!-- section -->\n\n<!-- section -->\n# Part 2\n")
+	sections, err := splitSections(b, "<!-- section -->")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 3 {
+		t.Fatalf("got %d sections, want 3", len(sections))
+	}
+	md, err := Parse(".", sections[1], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(md.Contents) != 2 {
+		t.Errorf("got %d pages in the second section, want 2", len(md.Contents))
+	}
+	if _, err := splitSections(b, " "); err == nil {
+		t.Error("expected error for empty marker but got none")
+	}
+}
+
+func TestParseLinkedImage(t *testing.T) {
+	b := []byte("# Title\n\n[![a](../testdata/test.png)](https://example.com)\n")
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := md.Contents[0].Images
+	if len(images) != 1 {
+		t.Fatalf("got %d images, want 1", len(images))
+	}
+	want, err := deck.NewImageFromMarkdown("../testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if images[0].Equivalent(want) {
+		t.Error("linked image should not be equivalent to the image without the link")
+	}
+	want.SetLink("https://example.com")
+	if !images[0].Equivalent(want) {
+		t.Error("linked image should be equivalent to the image with the same link")
+	}
+}
+
+func TestParseImageAlt(t *testing.T) {
+	b := []byte("# Title\n\n![Monthly *sales* chart](../testdata/test.png)\n")
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := md.Contents[0].Images
+	if len(images) != 1 {
+		t.Fatalf("got %d images, want 1", len(images))
+	}
+	if got, want := images[0].Alt(), "Monthly sales chart"; got != want {
+		t.Errorf("got alt %q, want %q", got, want)
+	}
+}
+
+func TestParseTableStyle(t *testing.T) {
+	b := []byte("<!-- {\"layout\": \"title-and-body\"} -->\n# Title\n\n<!-- {\"table\": {\"header_background\": \"#336699\", \"border_width\": 2}} -->\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n| c |\n|---|\n| 3 |\n")
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := md.Contents[0]
+	if c.Layout != "title-and-body" {
+		t.Errorf("got layout %q, table config should not reset the page config", c.Layout)
+	}
+	if len(c.Tables) != 2 {
+		t.Fatalf("got %d tables, want 2", len(c.Tables))
+	}
+	want := &deck.TableStyleOverride{HeaderBackground: "#336699", BorderWidth: 2}
+	if diff := cmp.Diff(want, c.Tables[0].Style); diff != "" {
+		t.Errorf("style mismatch (-want +got):\n%s", diff)
+	}
+	if c.Tables[1].Style != nil {
+		t.Errorf("got style %+v, the table config applies only to the following table", c.Tables[1].Style)
+	}
+}
+
+func TestParseNestedListMarkers(t *testing.T) {
+	b := []byte("# Title\n\n1. one\n   - a\n     1. i\n        - x\n          - y\n2. two\n")
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type item struct {
+		Bullet  deck.Bullet
+		Nesting int
+	}
+	var got []item
+	for _, p := range md.Contents[0].Bodies[0].Paragraphs {
+		got = append(got, item{p.Bullet, p.Nesting})
+	}
+	want := []item{
+		{deck.BulletNumbered, 0},
+		{deck.BulletDash, 1},
+		{deck.BulletNumbered, 2},
+		{deck.BulletDash, 3},
+		{deck.BulletDash, 4},
+		{deck.BulletNumbered, 0},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("list items mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseTableMerges(t *testing.T) {
+	table := []byte("| Name | Scores | < |\n|---|---|---|\n| ^ | Math | English |\n| Alice | 90 | 80 |\n| Bob | ^ | 70 |\n")
+	b := append([]byte("---\nmergeTableCells: true\n---\n"), table...)
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := md.Contents[0].Tables[0]
+	want := []*deck.TableMerge{
+		{Row: 0, Column: 0, RowSpan: 2, ColumnSpan: 1},
+		{Row: 0, Column: 1, RowSpan: 1, ColumnSpan: 2},
+		{Row: 2, Column: 1, RowSpan: 2, ColumnSpan: 1},
+	}
+	if diff := cmp.Diff(want, got.Merges); diff != "" {
+		t.Errorf("merges mismatch (-want +got):\n%s", diff)
+	}
+	if frags := got.Rows[1].Cells[0].Fragments; len(frags) != 0 {
+		t.Errorf("got fragments %v, merged cell should be empty", frags)
+	}
+
+	if _, err := Parse(".", []byte("---\nmergeTableCells: true\n---\n| < | a |\n|---|---|\n| 1 | 2 |\n"), nil); err == nil {
+		t.Error("expected error for merge marker without a cell to merge into but got none")
+	}
+
+	// Without mergeTableCells, the markers are text.
+	md, err = Parse(".", table, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = md.Contents[0].Tables[0]
+	if len(got.Merges) != 0 {
+		t.Errorf("got merges %v, want none", got.Merges)
+	}
+	if frags := got.Rows[0].Cells[2].Fragments; len(frags) != 1 || frags[0].Value != "<" {
+		t.Errorf("got fragments %v, want the marker kept as text", frags)
+	}
+}
+
+func TestParseImageSize(t *testing.T) {
+	b := []byte("# Title\n\n![a](../testdata/test.png){width=50%}\n\nsee ![b](../testdata/test.jpeg){width=300 height=\"200pt\"} here\n\n![c](../testdata/test.png){.class}\n")
+	md, err := Parse(".", b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := md.Contents[0]
+	if len(c.Images) != 3 {
+		t.Fatalf("got %d images, want 3", len(c.Images))
+	}
+	for i, want := range [][2]string{{"50%", ""}, {"300", "200pt"}, {"", ""}} {
+		if w, h := c.Images[i].Size(); w != want[0] || h != want[1] {
+			t.Errorf("image %d: got size %q x %q, want %q x %q", i, w, h, want[0], want[1])
+		}
+	}
+	var texts []string
+	for _, body := range c.Bodies {
+		for _, p := range body.Paragraphs {
+			for _, f := range p.Fragments {
+				texts = append(texts, f.Value)
+			}
+		}
+	}
+	if got := strings.Join(texts, "|"); strings.Contains(got, "width") || !strings.Contains(got, "{.class}") {
+		t.Errorf("attributes of the size should be removed from the text, and others kept: %q", got)
+	}
+
+	if _, err := Parse(".", []byte("![a](../testdata/test.png){width=wide}\n"), nil); err == nil {
+		t.Error("expected error for invalid size but got none")
+	}
+}
@@ -0,0 +1,64 @@
+package md
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/k1LoW/deck"
+	"github.com/k1LoW/errors"
+)
+
+// CreateMultiple splits the markdown into sections on the lines equal to splitOn,
+// and creates a presentation for each section with the slides of the section applied.
+// Relative paths of images are resolved from the current directory.
+func CreateMultiple(ctx context.Context, b []byte, splitOn string, opts ...deck.Option) (_ []*deck.Deck, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	sections, err := splitSections(b, splitOn)
+	if err != nil {
+		return nil, err
+	}
+	contents := make([]deck.Slides, 0, len(sections))
+	for i, section := range sections {
+		md, err := Parse(".", section, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse section %d: %w", i, err)
+		}
+		ss, err := md.ToSlides(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert section %d to slides: %w", i, err)
+		}
+		contents = append(contents, ss)
+	}
+	return deck.CreateMultiple(ctx, contents, opts...)
+}
+
+// splitSections splits the markdown on the lines equal to the marker, ignoring surrounding whitespace.
+// Sections without any content are dropped.
+func splitSections(b []byte, marker string) ([][]byte, error) {
+	marker = string(bytes.TrimSpace([]byte(marker)))
+	if marker == "" {
+		return nil, fmt.Errorf("section marker is empty")
+	}
+	var (
+		sections [][]byte
+		current  []byte
+	)
+	flush := func() {
+		if len(bytes.TrimSpace(current)) > 0 {
+			sections = append(sections, current)
+		}
+		current = nil
+	}
+	for line := range bytes.Lines(b) {
+		if string(bytes.TrimSpace(line)) == marker {
+			flush()
+			continue
+		}
+		current = append(current, line...)
+	}
+	flush()
+	return sections, nil
+}
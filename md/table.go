@@ -1,13 +1,17 @@
 package md
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/k1LoW/deck"
 	"github.com/yuin/goldmark/ast"
 	east "github.com/yuin/goldmark/extension/ast"
 )
 
 // parseTable parses an east.Table node and converts it to our Table structure.
-func parseTable(tableNode *east.Table, baseDir string, b []byte, breaks bool) (*deck.Table, error) {
+// The merge markers of the cells are parsed only if mergeTableCells is true, and are kept as text otherwise.
+func parseTable(tableNode *east.Table, baseDir string, b []byte, breaks, mergeTableCells bool) (*deck.Table, error) {
 	table := &deck.Table{
 		Rows: []*deck.TableRow{},
 	}
@@ -32,9 +36,85 @@ func parseTable(tableNode *east.Table, baseDir string, b []byte, breaks bool) (*
 		}
 	}
 
+	if mergeTableCells {
+		merges, err := parseTableMerges(table)
+		if err != nil {
+			return nil, err
+		}
+		table.Merges = merges
+	}
+
 	return table, nil
 }
 
+const (
+	mergeLeftMarker = "<" // a cell consisting of only "<" is merged into the cell on its left
+	mergeUpMarker   = "^" // a cell consisting of only "^" is merged into the cell above
+)
+
+// parseTableMerges parses the merge markers of the cells into merges, clearing the content of the merged cells.
+func parseTableMerges(table *deck.Table) ([]*deck.TableMerge, error) {
+	marker := func(r, c int) string {
+		if r >= len(table.Rows) || c >= len(table.Rows[r].Cells) {
+			return ""
+		}
+		var text strings.Builder
+		for _, f := range table.Rows[r].Cells[c].Fragments {
+			text.WriteString(f.Value)
+		}
+		switch t := strings.TrimSpace(text.String()); t {
+		case mergeLeftMarker, mergeUpMarker:
+			return t
+		}
+		return ""
+	}
+	var merges []*deck.TableMerge
+	covered := map[[2]int]bool{}
+	for r, row := range table.Rows {
+		for c := range row.Cells {
+			if covered[[2]int{r, c}] || marker(r, c) != "" {
+				continue
+			}
+			colSpan := 1
+			for marker(r, c+colSpan) == mergeLeftMarker {
+				colSpan++
+			}
+			rowSpan := 1
+		rows:
+			for marker(r+rowSpan, c) == mergeUpMarker {
+				for j := 1; j < colSpan; j++ {
+					if marker(r+rowSpan, c+j) == "" {
+						break rows
+					}
+				}
+				rowSpan++
+			}
+			if rowSpan == 1 && colSpan == 1 {
+				continue
+			}
+			for i := r; i < r+rowSpan; i++ {
+				for j := c; j < c+colSpan; j++ {
+					covered[[2]int{i, j}] = true
+				}
+			}
+			merges = append(merges, &deck.TableMerge{Row: r, Column: c, RowSpan: rowSpan, ColumnSpan: colSpan})
+		}
+	}
+	for r, row := range table.Rows {
+		for c, cell := range row.Cells {
+			m := marker(r, c)
+			if m == "" {
+				continue
+			}
+			if !covered[[2]int{r, c}] {
+				return nil, fmt.Errorf("table cell %q at row %d column %d has no cell to merge into", m, r+1, c+1)
+			}
+			cell.Fragments = []*deck.Fragment{}
+		}
+	}
+	return merges, nil
+}
+
 // parseTableRow parses a table row (header or regular) and extracts cells.
 func parseTableRow(rowNode ast.Node, baseDir string, b []byte, breaks, isHeader bool) (*deck.TableRow, error) {
 	row := &deck.TableRow{
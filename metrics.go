@@ -0,0 +1,105 @@
+package deck
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Error types counted in Metrics.Errors.
+const (
+	ErrorTypeApply       = "apply"
+	ErrorTypeBatchUpdate = "batch_update"
+	ErrorTypeRefresh     = "refresh"
+	ErrorTypeUpload      = "upload"
+	ErrorTypeAPI         = "api"
+)
+
+// Metrics is a snapshot of the counters of operations performed by a Deck.
+// The counters only increase, so they can be exported as counters of a monitoring system,
+// e.g. by a prometheus.Collector that calls Deck.Metrics on each collection.
+type Metrics struct {
+	Applies        int64            `json:"applies"`
+	SlidesCreated  int64            `json:"slides_created"`
+	SlidesUpdated  int64            `json:"slides_updated"`
+	SlidesDeleted  int64            `json:"slides_deleted"`
+	ImagesUploaded int64            `json:"images_uploaded"`
+	APICalls       int64            `json:"api_calls"`
+	Errors         map[string]int64 `json:"errors,omitempty"` // key: error type
+}
+
+// metrics holds the counters of operations performed by a Deck.
+// It is safe for concurrent use.
+type metrics struct {
+	applies        atomic.Int64
+	slidesCreated  atomic.Int64
+	slidesUpdated  atomic.Int64
+	slidesDeleted  atomic.Int64
+	imagesUploaded atomic.Int64
+	apiCalls       atomic.Int64
+
+	mu     sync.Mutex
+	errors map[string]int64
+}
+
+func (m *metrics) addError(errorType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.errors == nil {
+		m.errors = map[string]int64{}
+	}
+	m.errors[errorType]++
+}
+
+func (m *metrics) snapshot() Metrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return Metrics{
+		Applies:        m.applies.Load(),
+		SlidesCreated:  m.slidesCreated.Load(),
+		SlidesUpdated:  m.slidesUpdated.Load(),
+		SlidesDeleted:  m.slidesDeleted.Load(),
+		ImagesUploaded: m.imagesUploaded.Load(),
+		APICalls:       m.apiCalls.Load(),
+		Errors:         maps.Clone(m.errors),
+	}
+}
+
+// transport wraps the base RoundTripper to count outbound API calls and API errors.
+func (m *metrics) transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &metricsTransport{base: base, m: m}
+}
+
+type metricsTransport struct {
+	base http.RoundTripper
+	m    *metrics
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.m.apiCalls.Add(1)
+	res, err := t.base.RoundTrip(req)
+	if err != nil || res.StatusCode >= http.StatusBadRequest {
+		t.m.addError(ErrorTypeAPI)
+	}
+	return res, err
+}
+
+// Metrics returns a snapshot of the counters of operations performed by the Deck.
+// The counters are safe for concurrent use, so it does not wait for a running operation such as Apply.
+func (d *Deck) Metrics() Metrics {
+	return d.stats().snapshot()
+}
+
+// stats returns the counters of the Deck, creating them on first use unless they are shared with another Deck.
+func (d *Deck) stats() *metrics {
+	d.metricsOnce.Do(func() {
+		if d.metrics == nil {
+			d.metrics = &metrics{}
+		}
+	})
+	return d.metrics
+}
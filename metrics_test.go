@@ -0,0 +1,59 @@
+package deck
+
+import (
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("before")}
+	d := newFakeDeck(t, s)
+
+	ss := Slides{{
+		Layout: "Title and Content",
+		Titles: []string{"after"},
+		TitleBodies: []*Body{{
+			Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "after"}}}},
+		}},
+	}}
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+
+	got := d.Metrics()
+	if got.Applies != 1 {
+		t.Errorf("got Applies %d, want 1", got.Applies)
+	}
+	if got.SlidesUpdated != 1 {
+		t.Errorf("got SlidesUpdated %d, want 1", got.SlidesUpdated)
+	}
+	if got.SlidesCreated != 0 || got.SlidesDeleted != 0 {
+		t.Errorf("got SlidesCreated %d and SlidesDeleted %d, want 0", got.SlidesCreated, got.SlidesDeleted)
+	}
+	if want := int64(len(s.batchUpdates)) + 1; got.APICalls < want {
+		t.Errorf("got APICalls %d, want at least %d", got.APICalls, want)
+	}
+	if len(got.Errors) != 0 {
+		t.Errorf("got Errors %v, want none", got.Errors)
+	}
+
+	if _, err := d.Apply(t.Context(), Slides{{Layout: "not found"}}); err == nil {
+		t.Fatal("expected error")
+	}
+	got = d.Metrics()
+	if got.Applies != 2 {
+		t.Errorf("got Applies %d, want 2", got.Applies)
+	}
+	if got.Errors[ErrorTypeApply] != 1 {
+		t.Errorf("got Errors[%q] %d, want 1", ErrorTypeApply, got.Errors[ErrorTypeApply])
+	}
+
+	// A slide that is already up to date sends no requests and is not counted as updated.
+	s = &fakeServer{presentation: newFakePresentation("same")}
+	d = newFakeDeck(t, s)
+	if _, err := d.Apply(t.Context(), Slides{{Layout: "Title and Content", Titles: []string{"same"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Metrics().SlidesUpdated; got != 0 {
+		t.Errorf("got SlidesUpdated %d for an unchanged slide, want 0 (batch updates: %d)", got, len(s.batchUpdates))
+	}
+}
@@ -0,0 +1,61 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k1LoW/errors"
+)
+
+// CreateMultiple creates a new presentation for each of the contents and applies the slides to it.
+// The presentations share the authentication, the API services, the folder, the rate limit and the metrics.
+// On error, it returns the decks created so far along with the error.
+// Closing the decks does not close the Storage given in the options, which the caller closes after them.
+func CreateMultiple(ctx context.Context, contents []Slides, opts ...Option) (_ []*Deck, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d, err := newDeck(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return d.createMultiple(ctx, contents, opts...)
+}
+
+func (d *Deck) createMultiple(ctx context.Context, contents []Slides, opts ...Option) ([]*Deck, error) {
+	decks := make([]*Deck, 0, len(contents))
+	for i, ss := range contents {
+		s, err := d.sibling(opts...)
+		if err != nil {
+			return decks, err
+		}
+		if err := s.create(ctx); err != nil {
+			return decks, fmt.Errorf("failed to create presentation %d: %w", i, err)
+		}
+		decks = append(decks, s)
+		if _, err := s.Apply(ctx, ss); err != nil {
+			return decks, fmt.Errorf("failed to apply slides to presentation %d: %w", i, err)
+		}
+	}
+	return decks, nil
+}
+
+// sibling returns a new Deck with the options applied that shares the API services of d.
+func (d *Deck) sibling(opts ...Option) (*Deck, error) {
+	s, err := newDeckWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.logger = d.logger
+	s.srv = d.srv
+	s.driveSrv = d.driveSrv
+	s.imageCache = d.imageCache
+	// The folder resolved from WithFolderName, the rate limit and the metrics are shared,
+	// since the API services count and throttle the calls of d.
+	s.folderID = d.folderID
+	s.rateLimiter = d.rateLimiter
+	s.metrics = d.stats()
+	// The storages given in the options are shared by the siblings, so none of them closes the storages.
+	s.storageShared = true
+	return s, nil
+}
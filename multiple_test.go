@@ -0,0 +1,65 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestCreateMultiple(t *testing.T) {
+	var (
+		created []string
+		parents [][]string
+	)
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			f := &drive.File{}
+			if err := json.NewDecoder(r.Body).Decode(f); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f.Id = fmt.Sprintf("presentation-%d", len(created))
+			created = append(created, f.Id)
+			parents = append(parents, f.Parents)
+			_ = json.NewEncoder(w).Encode(f)
+		},
+	}
+	d := newFakeDeck(t, s)
+	// The folder resolved from WithFolderName is not in the options applied to the siblings.
+	d.folderID = "resolved-folder"
+	contents := []Slides{
+		{{Layout: "Title and Content", Titles: []string{"Section 1"}}},
+		{{Layout: "Title and Content", Titles: []string{"Section 2"}}},
+		{{Layout: "Title and Content", Titles: []string{"Section 3"}}},
+	}
+
+	decks, err := d.createMultiple(t.Context(), contents, WithDefaultName("Course"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decks) != 3 {
+		t.Fatalf("got %d decks, want 3", len(decks))
+	}
+	for i, dd := range decks {
+		if want := fmt.Sprintf("presentation-%d", i); dd.ID() != want {
+			t.Errorf("got ID %q, want %q", dd.ID(), want)
+		}
+		if dd.srv != d.srv || dd.driveSrv != d.driveSrv {
+			t.Errorf("deck %d does not share the services", i)
+		}
+		if !slices.Equal(parents[i], []string{"resolved-folder"}) {
+			t.Errorf("got parents %v of presentation %d, want the resolved folder", parents[i], i)
+		}
+	}
+	if got := decks[0].Metrics().Applies; got != 3 || d.Metrics().Applies != 3 {
+		t.Errorf("got %d and %d applies, want the metrics of the 3 applies shared", got, d.Metrics().Applies)
+	}
+	if len(s.batchUpdates) == 0 {
+		t.Error("expected slides to be applied but got no batch updates")
+	}
+}
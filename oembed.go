@@ -0,0 +1,83 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// Size of the iframe to embed a slide, which is the default size of the embed code of Google Slides.
+const (
+	oEmbedWidth  = 960
+	oEmbedHeight = 569
+)
+
+// OEmbed is an oEmbed payload of the rich type to embed a slide.
+type OEmbed struct {
+	Type            string `json:"type"`
+	Version         string `json:"version"`
+	Title           string `json:"title,omitempty"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty"`
+	HTML            string `json:"html"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+}
+
+// ExportSlideOEmbed returns an oEmbed payload to embed the slide at the index.
+// The embedded slide is visible only if the presentation is published to the web,
+// and the thumbnail URL is valid only for a limited time.
+func (d *Deck) ExportSlideOEmbed(ctx context.Context, index int) (_ OEmbed, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.refresh(ctx); err != nil {
+		return OEmbed{}, err
+	}
+	if index < 0 || len(d.presentation.Slides) <= index {
+		return OEmbed{}, fmt.Errorf("index out of range: %d", index)
+	}
+	page := d.presentation.Slides[index]
+
+	var thumbnail *slides.Thumbnail
+	if err := d.call(ctx, "get thumbnail", func(ctx context.Context) (err error) {
+		thumbnail, err = d.srv.Presentations.Pages.GetThumbnail(d.id, page.ObjectId).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return OEmbed{}, fmt.Errorf("failed to get thumbnail: %w", err)
+	}
+
+	title := d.presentation.Title
+	layoutObjectIdMap := map[string]*slides.Page{}
+	for _, l := range d.presentation.Layouts {
+		layoutObjectIdMap[l.ObjectId] = l
+	}
+	if titles := convertToSlide(page, layoutObjectIdMap).Titles; len(titles) > 0 {
+		title = strings.Join(titles, " ")
+	}
+
+	embedURL := fmt.Sprintf("%sembed?start=false&loop=false&slide=id.%s", PresentationIDtoURL(d.id), page.ObjectId)
+	return OEmbed{
+		Type:            "rich",
+		Version:         "1.0",
+		Title:           title,
+		ProviderName:    "Google Slides",
+		ProviderURL:     "https://docs.google.com/presentation/",
+		ThumbnailURL:    thumbnail.ContentUrl,
+		ThumbnailWidth:  int(thumbnail.Width),
+		ThumbnailHeight: int(thumbnail.Height),
+		HTML: fmt.Sprintf(`<iframe src="%s" frameborder="0" width="%d" height="%d" allowfullscreen="true"></iframe>`,
+			html.EscapeString(embedURL), oEmbedWidth, oEmbedHeight),
+		Width:  oEmbedWidth,
+		Height: oEmbedHeight,
+	}, nil
+}
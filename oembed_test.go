@@ -0,0 +1,38 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExportSlideOEmbed(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a", "b")}
+	s.presentation.Title = "Presentation"
+	d := newFakeDeck(t, s)
+
+	got, err := d.ExportSlideOEmbed(t.Context(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := OEmbed{
+		Type:            "rich",
+		Version:         "1.0",
+		Title:           "b",
+		ProviderName:    "Google Slides",
+		ProviderURL:     "https://docs.google.com/presentation/",
+		ThumbnailURL:    "https://example.com/thumbnails/slide-1",
+		ThumbnailWidth:  1600,
+		ThumbnailHeight: 900,
+		HTML:            `<iframe src="https://docs.google.com/presentation/d/fake-presentation/embed?start=false&amp;loop=false&amp;slide=id.slide-1" frameborder="0" width="960" height="569" allowfullscreen="true"></iframe>`,
+		Width:           960,
+		Height:          569,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("oEmbed mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := d.ExportSlideOEmbed(t.Context(), 2); err == nil {
+		t.Error("expected error for out of range index but got none")
+	}
+}
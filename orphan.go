@@ -0,0 +1,64 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/drive/v3"
+)
+
+// CleanupOrphanedImages deletes the temporary images uploaded to Google Drive more than olderThan ago,
+// which are left behind when an apply fails before deleting them. It returns the number of images deleted.
+// It is supported only when images are uploaded to Google Drive.
+func (d *Deck) CleanupOrphanedImages(ctx context.Context, olderThan time.Duration) (_ int, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.storage != nil || d.imageUploadCmd != "" {
+		return 0, fmt.Errorf("cleaning up orphaned images is supported only for Google Drive")
+	}
+	if olderThan < 0 {
+		return 0, fmt.Errorf("invalid age of orphaned images: %s", olderThan)
+	}
+	q := fmt.Sprintf("name contains '%s' and createdTime < '%s' and trashed = false",
+		tempFilePrefix, time.Now().Add(-olderThan).UTC().Format(time.RFC3339))
+	if d.folderID != "" {
+		q += fmt.Sprintf(" and '%s' in parents", d.folderID)
+	}
+	var files []*drive.File
+	var pageToken string
+	for {
+		var list *drive.FileList
+		if err := d.call(ctx, "list orphaned images", func(ctx context.Context) (err error) {
+			call := d.driveSrv.Files.List().Q(q).Fields("nextPageToken, files(id, name)").
+				SupportsAllDrives(true).IncludeItemsFromAllDrives(true)
+			if pageToken != "" {
+				call = call.PageToken(pageToken)
+			}
+			list, err = call.Context(ctx).Do()
+			return err
+		}); err != nil {
+			return 0, fmt.Errorf("failed to list orphaned images: %w", err)
+		}
+		files = append(files, list.Files...)
+		if list.NextPageToken == "" {
+			break
+		}
+		pageToken = list.NextPageToken
+	}
+
+	var deleted int
+	for _, f := range files {
+		if err := d.deleteOrTrashFile(ctx, f.Id); err != nil {
+			return deleted, fmt.Errorf("failed to delete orphaned image %s: %w", f.Name, err)
+		}
+		deleted++
+	}
+	d.logger.Info("cleaned up orphaned images", slog.Int("count", deleted))
+	return deleted, nil
+}
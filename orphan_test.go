@@ -0,0 +1,61 @@
+package deck
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestCleanupOrphanedImages(t *testing.T) {
+	var (
+		q       string
+		deleted []string
+	)
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/drive/v3/files":
+				q = r.URL.Query().Get("q")
+				list := &drive.FileList{}
+				switch r.URL.Query().Get("pageToken") {
+				case "":
+					list.Files = []*drive.File{{Id: "tmp-1", Name: tempFilePrefix + "1"}}
+					list.NextPageToken = "next"
+				case "next":
+					list.Files = []*drive.File{{Id: "tmp-2", Name: tempFilePrefix + "2"}}
+				}
+				_ = json.NewEncoder(w).Encode(list)
+			case r.Method == http.MethodGet:
+				_ = json.NewEncoder(w).Encode(&drive.File{Capabilities: &drive.FileCapabilities{CanDelete: true}})
+			case r.Method == http.MethodDelete:
+				deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/drive/v3/files/"))
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	}
+	d := newFakeDeck(t, s, WithFolderID("folder"))
+
+	n, err := d.CleanupOrphanedImages(t.Context(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || strings.Join(deleted, ",") != "tmp-1,tmp-2" {
+		t.Errorf("got %d deleted %v, want tmp-1 and tmp-2", n, deleted)
+	}
+	for _, want := range []string{"name contains '" + tempFilePrefix + "'", "createdTime < '", "'folder' in parents"} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query %q does not contain %q", q, want)
+		}
+	}
+
+	if _, err := newFakeDeck(t, s, WithStorage(&countingStorage{})).CleanupOrphanedImages(t.Context(), time.Hour); err == nil {
+		t.Error("expected error for storage other than Google Drive but got none")
+	}
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
 	"slices"
 	"sync"
+	"time"
 
+	"github.com/k1LoW/errors"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
-	"google.golang.org/api/slides/v1"
 )
 
-const maxPreloadWorkersNum = 4
+const defaultConcurrency = 4
 
 // currentImageData holds the result of parallel image fetching.
 type currentImageData struct {
@@ -28,6 +32,7 @@ type imageToPreload struct {
 	objectID       string // objectID of existing image
 	isFromMarkdown bool   // whether this image is from markdown
 	externalLink   string // external link associated with the image, if any
+	alt            string // alternative text of the image from markdown, if any
 }
 
 // imageResult holds the result of image processing.
@@ -39,6 +44,8 @@ type imageResult struct {
 }
 
 // preloadCurrentImages pre-fetches current images for all slides that will be processed.
+// With continueOnImageError, an image that fails to be fetched is preloaded as an image without data,
+// which matches no image so that it is replaced, and the errors are returned together with the result.
 func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map[int]*currentImageData, error) {
 	result := make(map[int]*currentImageData)
 
@@ -60,12 +67,8 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 							existingURL:    element.Image.ContentUrl,
 							objectID:       element.ObjectId,
 							isFromMarkdown: element.Description == descriptionImageFromMarkdown,
-							externalLink: func(img *slides.Image) string {
-								if img.ImageProperties != nil && img.ImageProperties.Link != nil {
-									return img.ImageProperties.Link.Url
-								}
-								return ""
-							}(element.Image),
+							alt:            markdownImageAlt(element),
+							externalLink:   imageLink(element.Image),
 						})
 						imageIndexInSlide++
 					}
@@ -80,9 +83,14 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 	d.logger.Info("preloading current images", slog.Int("count", len(imagesToPreload)))
 
 	// Process images in parallel
-	sem := semaphore.NewWeighted(maxPreloadWorkersNum)
+	p := d.newProgress(ProgressPhasePreloading, len(imagesToPreload))
+	sem := semaphore.NewWeighted(int64(d.workersNum()))
 	eg, ctx := errgroup.WithContext(ctx)
 	resultCh := make(chan imageResult, len(imagesToPreload))
+	var (
+		errsMu sync.Mutex
+		errs   []error
+	)
 
 	for _, imgToPreload := range imagesToPreload {
 		eg.Go(func() error {
@@ -96,15 +104,25 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 			var err error
 
 			// Create Image from existing URL
-			if imgToPreload.isFromMarkdown {
-				image, err = NewImageFromMarkdown(imgToPreload.existingURL)
-			} else {
-				image, err = NewImage(imgToPreload.existingURL)
-			}
+			image, err = retryImageFetch(ctx, func() (*Image, error) {
+				if d.imageCache != nil {
+					return d.imageCache.newImage(ctx, imgToPreload.existingURL, imgToPreload.isFromMarkdown)
+				}
+				return d.fetchImage(imgToPreload.existingURL, imgToPreload.isFromMarkdown)
+			})
 			if err != nil {
-				return fmt.Errorf("failed to preload image from URL %s: %w", imgToPreload.existingURL, err)
+				err = fmt.Errorf("failed to preload image from URL %s: %w", imgToPreload.existingURL, err)
+				if !d.continueOnImgError || ctx.Err() != nil {
+					return err
+				}
+				d.logger.Warn("failed to preload image, it will be replaced", slog.Any("error", err))
+				errsMu.Lock()
+				errs = append(errs, err)
+				errsMu.Unlock()
+				image = &Image{url: imgToPreload.existingURL, fromMarkdown: imgToPreload.isFromMarkdown}
 			}
 			image.link = imgToPreload.externalLink
+			image.alt = imgToPreload.alt
 
 			resultCh <- imageResult{
 				slideIndex: imgToPreload.slideIndex,
@@ -112,6 +130,7 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 				image:      image,
 				objectID:   imgToPreload.objectID,
 			}
+			p.done(imgToPreload.slideIndex)
 			return nil
 		})
 	}
@@ -145,7 +164,14 @@ func (d *Deck) preloadCurrentImages(ctx context.Context, actions []*action) (map
 		}
 	}
 
+	if d.imageCache != nil {
+		d.logger.Debug("image cache stats",
+			slog.Int64("hits", d.imageCache.hits.Load()), slog.Int64("misses", d.imageCache.misses.Load()))
+	}
 	d.logger.Info("preloaded current images")
+	if len(errs) > 0 {
+		return result, fmt.Errorf("failed to preload %d of %d images: %w", len(errs), len(imagesToPreload), errors.Join(errs...))
+	}
 	return result, nil
 }
 
@@ -155,12 +181,48 @@ type uploadedImageInfo struct {
 	image      *Image
 }
 
+// uploadRefs counts the runs referencing each uploaded image across decks in the process.
+// A storage may return the same uploaded ID for the same content, so that an image uploaded by
+// concurrent runs must be deleted only when the last run referencing it finishes.
+var uploadRefs = &refCounter{counts: map[string]int{}}
+
+// refCounter is a reference counter keyed by uploaded ID.
+type refCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// acquire adds a reference to the uploaded image.
+func (c *refCounter) acquire(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[id]++
+}
+
+// release removes a reference to the uploaded image and reports whether it was the last one.
+func (c *refCounter) release(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[id]--
+	if c.counts[id] > 0 {
+		return false
+	}
+	delete(c.counts, id)
+	return true
+}
+
 // startUploadingImages starts uploading new images asynchronously and returns a channel for cleanup.
+// Numbers of uploaded and reused images are counted in stats if it is not nil.
 func (d *Deck) startUploadingImages(
-	ctx context.Context, actions []*action, currentImages map[int]*currentImageData) <-chan uploadedImageInfo {
+	ctx context.Context, actions []*action, currentImages map[int]*currentImageData, stats *imageStats) <-chan uploadedImageInfo {
+	if stats == nil {
+		stats = &imageStats{}
+	}
 
-	// Collect all images that need uploading
+	// Collect all images that need uploading.
+	// Physically identical images are uploaded only once and share the resulting public URL.
 	var imagesToUpload []*Image
+	sameImages := map[string][]*Image{} // key: SHA-256 of the image bytes
 
 	for _, action := range actions {
 		switch action.actionType {
@@ -176,9 +238,29 @@ func (d *Deck) startUploadingImages(
 						return currentImage.Equivalent(image)
 					})
 				}
-				if !found && image.IsUploadNeeded() && !slices.Contains(imagesToUpload, image) {
+				if found {
+					stats.reused.Add(1)
+					continue
+				}
+				if !image.IsUploadNeeded() {
+					continue
+				}
+				// An image equivalent to one on another slide, e.g. of a moved slide, is created from its URL.
+				if u := equivalentImageURL(currentImages, image); u != "" {
+					image.SetUploadResult(u, nil)
+					stats.reused.Add(1)
+					continue
+				}
+				key := hashHex(image.Bytes())
+				if slices.Contains(sameImages[key], image) {
+					continue
+				}
+				if len(sameImages[key]) == 0 {
 					imagesToUpload = append(imagesToUpload, image)
+				} else {
+					stats.reused.Add(1)
 				}
+				sameImages[key] = append(sameImages[key], image)
 			}
 		}
 	}
@@ -192,39 +274,64 @@ func (d *Deck) startUploadingImages(
 	d.logger.Info("starting image upload", slog.Int("count", len(imagesToUpload)))
 
 	// Mark all images as upload in progress
-	for _, image := range imagesToUpload {
-		image.StartUpload()
+	for _, images := range sameImages {
+		for _, image := range images {
+			image.StartUpload()
+		}
 	}
 
 	// Get storage instance
 	storage := d.getStorage()
+	p := d.newProgress(ProgressPhaseUploading, len(imagesToUpload))
 
 	// Start uploading images asynchronously
 	go func() {
 		// Process images in parallel
-		sem := semaphore.NewWeighted(maxPreloadWorkersNum)
+		sem := semaphore.NewWeighted(int64(d.workersNum()))
 		eg, ctx := errgroup.WithContext(ctx)
 
 		for _, image := range imagesToUpload {
+			images := sameImages[hashHex(image.Bytes())]
 			eg.Go(func() error {
 				if err := sem.Acquire(ctx, 1); err != nil {
 					// Context canceled, set upload error on remaining images
-					image.SetUploadResult("", err)
+					for _, i := range images {
+						i.SetUploadResult("", err)
+					}
 					return err
 				}
 				defer sem.Release(1)
 
-				mimeType := string(image.mimeType)
-				publicURL, uploadedID, err := storage.Upload(ctx, image.Bytes(), mimeType)
+				data, mimeType, err := d.imageUploadData(image)
 				if err != nil {
-					image.SetUploadResult("", fmt.Errorf("failed to upload image: %w", err))
+					for _, i := range images {
+						i.SetUploadResult("", err)
+					}
 					return err
 				}
+				publicURL, uploadedID, err := storage.Upload(ctx, data, string(mimeType))
+				if err != nil {
+					d.stats().addError(ErrorTypeUpload)
+					for _, i := range images {
+						i.SetUploadResult("", fmt.Errorf("failed to upload image: %w", err))
+					}
+					return err
+				}
+				d.stats().imagesUploaded.Add(1)
+				stats.uploaded.Add(1)
+				p.done(-1)
+				if !d.keepsUploadedImages() {
+					uploadRefs.acquire(uploadedID)
+				}
 
 				// Set successful upload result
-				image.SetUploadResult(publicURL, nil)
+				for _, i := range images {
+					i.SetUploadResult(publicURL, nil)
+				}
 
-				uploadedCh <- uploadedImageInfo{uploadedID: uploadedID, image: image}
+				if !d.keepsUploadedImages() {
+					uploadedCh <- uploadedImageInfo{uploadedID: uploadedID, image: image}
+				}
 				return nil
 			})
 		}
@@ -241,8 +348,9 @@ func (d *Deck) startUploadingImages(
 }
 
 // cleanupUploadedImages deletes uploaded images in parallel.
+// An image still referenced by another run is left to be deleted by the last run.
 func (d *Deck) cleanupUploadedImages(ctx context.Context, uploadedCh <-chan uploadedImageInfo) error {
-	sem := semaphore.NewWeighted(maxPreloadWorkersNum)
+	sem := semaphore.NewWeighted(int64(d.workersNum()))
 	var wg sync.WaitGroup
 
 	// Get storage instance
@@ -256,6 +364,10 @@ func (d *Deck) cleanupUploadedImages(ctx context.Context, uploadedCh <-chan uplo
 				wg.Wait()
 				return nil
 			}
+			if !uploadRefs.release(info.uploadedID) {
+				d.logger.Debug("uploaded image is still in use by another run", slog.String("id", info.uploadedID))
+				continue
+			}
 			// Try to acquire semaphore
 			if err := sem.Acquire(ctx, 1); err != nil {
 				return fmt.Errorf("failed to acquire semaphore: %w", err)
@@ -284,3 +396,126 @@ func (d *Deck) cleanupUploadedImages(ctx context.Context, uploadedCh <-chan uplo
 		}
 	}
 }
+
+// imageFetchRetries is the number of times fetching an image is retried after a transient failure.
+const imageFetchRetries = 3
+
+// imageFetchBackoff is the wait before the first retry of fetching an image. It doubles on each retry.
+var imageFetchBackoff = 500 * time.Millisecond
+
+// retryImageFetch calls fetch, retrying with exponential backoff while it fails with a network error or a 5xx status.
+// It returns the last error if all the attempts fail or ctx is done while waiting.
+func retryImageFetch(ctx context.Context, fetch func() (*Image, error)) (*Image, error) {
+	backoff := imageFetchBackoff
+	for attempt := 0; ; attempt++ {
+		image, err := fetch()
+		if err == nil || attempt >= imageFetchRetries || !isTransientFetchError(err) {
+			return image, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientFetchError reports whether fetching an image failed with a network error or a 5xx status.
+func isTransientFetchError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *imageFetchStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= http.StatusInternalServerError
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// equivalentImageURL returns the URL of a current image on any slide that is equivalent to the image,
+// or an empty string if there is no such image.
+func equivalentImageURL(currentImages map[int]*currentImageData, image *Image) string {
+	for _, index := range slices.Sorted(maps.Keys(currentImages)) {
+		for _, currentImage := range currentImages[index].currentImages {
+			if currentImage != nil && currentImage.url != "" && currentImage.Equivalent(image) {
+				return currentImage.url
+			}
+		}
+	}
+	return ""
+}
+
+// imageUploadData returns the data and MIME type of the image to upload,
+// rasterizing SVG, flattening animated GIFs and downscaling as configured.
+func (d *Deck) imageUploadData(image *Image) ([]byte, MIMEType, error) {
+	data := image.Bytes()
+	mimeType := image.mimeType
+	if ct := http.DetectContentType(data); ct != string(mimeType) {
+		src := image.url
+		if src == "" {
+			src = "code block"
+		}
+		return nil, "", fmt.Errorf("content type of image from %s is %s, but %s is declared", src, ct, mimeType)
+	}
+	if image.svg != nil && d.svgScale > 0 && d.svgScale != 1 {
+		rasterized, err := rasterizeSVG(image.svg, d.svgScale)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to rasterize svg: %w", err)
+		}
+		data = rasterized
+	}
+	if d.flattenGIF && mimeType == MIMETypeImageGIF {
+		flattened, ok, err := flattenGIF(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to flatten gif: %w", err)
+		}
+		if ok {
+			data = flattened
+			mimeType = MIMETypeImagePNG
+		}
+	}
+	if d.maxImageDimension > 0 {
+		resized, err := downscaleImage(data, mimeType, d.maxImageDimension)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to downscale image: %w", err)
+		}
+		data = resized
+	}
+	return data, mimeType, nil
+}
+
+// keepsUploadedImages reports whether images uploaded to Google Drive are content-addressed or permanent,
+// in which case they must not be deleted after apply.
+func (d *Deck) keepsUploadedImages() bool {
+	return (d.contentAddressed || d.permanentImages) && d.storage == nil && d.imageUploadCmd == ""
+}
+
+// imageFetchTimeoutOrDefault returns the timeout for fetching an image from a URL.
+func (d *Deck) imageFetchTimeoutOrDefault() time.Duration {
+	if d.imageFetchTimeout <= 0 {
+		return defaultImageFetchTimeout
+	}
+	return d.imageFetchTimeout
+}
+
+// fetchImage creates an Image from the path or URL within the image fetch timeout of the deck.
+func (d *Deck) fetchImage(pathOrURL string, fromMarkdown bool) (*Image, error) {
+	i, err := newImageWithTimeout(pathOrURL, d.imageFetchTimeoutOrDefault())
+	if err != nil {
+		return nil, err
+	}
+	if fromMarkdown {
+		i.fromMarkdown = true
+	}
+	return i, nil
+}
+
+// workersNum returns the number of parallel workers for preloading, uploading, and cleaning up images.
+func (d *Deck) workersNum() int {
+	if d.concurrency < 1 {
+		return defaultConcurrency
+	}
+	return d.concurrency
+}
@@ -0,0 +1,324 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/slides/v1"
+)
+
+type countingStorage struct {
+	mu      sync.Mutex
+	uploads int
+	deletes map[string]int
+	data    [][]byte
+}
+
+func (s *countingStorage) Upload(ctx context.Context, data []byte, mimeType string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads++
+	s.data = append(s.data, data)
+	id := fmt.Sprintf("uploaded-%d", s.uploads)
+	return "https://example.com/" + id, id, nil
+}
+
+func (s *countingStorage) Delete(ctx context.Context, uploadedID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deletes == nil {
+		s.deletes = map[string]int{}
+	}
+	s.deletes[uploadedID]++
+	return nil
+}
+
+func TestStartUploadingImagesDeduplicatesByContent(t *testing.T) {
+	newPNG := func() *Image {
+		i, err := NewImageFromMarkdown("testdata/test.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i
+	}
+	logo1, logo2 := newPNG(), newPNG()
+	other, err := NewImageFromMarkdown("testdata/test.jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &countingStorage{}
+	d := &Deck{
+		logger:  slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		storage: storage,
+	}
+	actions := []*action{
+		{actionType: actionTypeAppend, index: 0, slide: &Slide{Images: []*Image{logo1, other}}},
+		{actionType: actionTypeAppend, index: 1, slide: &Slide{Images: []*Image{logo2, logo2}}},
+	}
+
+	uploadedCh := d.startUploadingImages(t.Context(), actions, nil, nil)
+	for _, i := range []*Image{logo1, logo2, other} {
+		if _, err := i.UploadInfo(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.cleanupUploadedImages(t.Context(), uploadedCh); err != nil {
+		t.Fatal(err)
+	}
+
+	if storage.uploads != 2 {
+		t.Errorf("got %d uploads, want 2", storage.uploads)
+	}
+	if logo1.webContentLink == "" || logo1.webContentLink != logo2.webContentLink {
+		t.Errorf("identical images should share the public URL: %q and %q", logo1.webContentLink, logo2.webContentLink)
+	}
+	if len(storage.deletes) != 2 {
+		t.Errorf("got %d deleted resources, want 2", len(storage.deletes))
+	}
+	for id, n := range storage.deletes {
+		if n != 1 {
+			t.Errorf("%s deleted %d times, want 1", id, n)
+		}
+	}
+}
+
+// sharedStorage returns the same uploaded ID for the same content, like a storage that deduplicates uploads.
+type sharedStorage struct {
+	countingStorage
+}
+
+func (s *sharedStorage) Upload(ctx context.Context, data []byte, mimeType string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads++
+	s.data = append(s.data, data)
+	id := "shared-" + hashHex(data)
+	return "https://example.com/" + id, id, nil
+}
+
+func TestCleanupUploadedImagesSharedAcrossDecks(t *testing.T) {
+	storage := &sharedStorage{}
+	newDeck := func() *Deck {
+		return &Deck{
+			logger:  slog.New(slog.NewJSONHandler(io.Discard, nil)),
+			storage: storage,
+		}
+	}
+	start := func(d *Deck) <-chan uploadedImageInfo {
+		i, err := NewImageFromMarkdown("testdata/test.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch := d.startUploadingImages(t.Context(), []*action{
+			{actionType: actionTypeAppend, index: 0, slide: &Slide{Images: []*Image{i}}},
+		}, nil, nil)
+		if _, err := i.UploadInfo(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		return ch
+	}
+	d1, d2 := newDeck(), newDeck()
+	ch1 := start(d1)
+	ch2 := start(d2)
+
+	if err := d1.cleanupUploadedImages(t.Context(), ch1); err != nil {
+		t.Fatal(err)
+	}
+	if len(storage.deletes) != 0 {
+		t.Fatalf("shared image was deleted while still in use: %v", storage.deletes)
+	}
+	if err := d2.cleanupUploadedImages(t.Context(), ch2); err != nil {
+		t.Fatal(err)
+	}
+	if len(storage.deletes) != 1 {
+		t.Errorf("got %d deleted images, want 1", len(storage.deletes))
+	}
+}
+
+func TestStartUploadingImagesReusesEquivalentImageOnOtherSlide(t *testing.T) {
+	moved, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.url = "https://example.com/current"
+	storage := &countingStorage{}
+	d := &Deck{
+		logger:  slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		storage: storage,
+	}
+	actions := []*action{
+		{actionType: actionTypeUpdate, index: 0, slide: &Slide{Images: []*Image{moved}}},
+	}
+	currentImages := map[int]*currentImageData{
+		0: {currentImageObjectIDMap: map[*Image]string{}},
+		1: {
+			currentImages:           []*Image{current},
+			currentImageObjectIDMap: map[*Image]string{current: "image-1"},
+		},
+	}
+	stats := &imageStats{}
+
+	uploadedCh := d.startUploadingImages(t.Context(), actions, currentImages, stats)
+	got, err := moved.UploadInfo(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.cleanupUploadedImages(t.Context(), uploadedCh); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.url != current.url {
+		t.Errorf("got %q, want %q", got.url, current.url)
+	}
+	if storage.uploads != 0 {
+		t.Errorf("got %d uploads, want 0", storage.uploads)
+	}
+	if stats.reused.Load() != 1 {
+		t.Errorf("got %d reused, want 1", stats.reused.Load())
+	}
+}
+
+func TestRetryImageFetch(t *testing.T) {
+	orig := imageFetchBackoff
+	imageFetchBackoff = time.Millisecond
+	t.Cleanup(func() { imageFetchBackoff = orig })
+	png, err := os.ReadFile("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		failures     int
+		status       int
+		wantErr      bool
+		wantRequests int
+	}{
+		{"recovers from 5xx", 2, http.StatusServiceUnavailable, false, 3},
+		{"gives up after retries", 10, http.StatusBadGateway, true, imageFetchRetries + 1},
+		{"does not retry 4xx", 10, http.StatusNotFound, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(requests.Add(1)) <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = w.Write(png)
+			}))
+			t.Cleanup(ts.Close)
+			u := ts.URL + "/image.png"
+			_, err := retryImageFetch(t.Context(), func() (*Image, error) {
+				return newImageWithTimeout(u, time.Second)
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), u) {
+				t.Errorf("error should contain the URL: %v", err)
+			}
+			if got := int(requests.Load()); got != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryImageFetchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	var calls int
+	_, err := retryImageFetch(ctx, func() (*Image, error) {
+		calls++
+		cancel()
+		return nil, &imageFetchStatusError{url: "https://example.com/image.png", statusCode: http.StatusInternalServerError}
+	})
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+func TestPreloadCurrentImagesContinueOnImageError(t *testing.T) {
+	png, err := os.ReadFile("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(png)
+	}))
+	t.Cleanup(ts.Close)
+	imageElement := func(id, path string) *slides.PageElement {
+		return &slides.PageElement{
+			ObjectId:    id,
+			Description: descriptionImageFromMarkdown,
+			Image:       &slides.Image{ContentUrl: ts.URL + path},
+		}
+	}
+	presentation := &slides.Presentation{Slides: []*slides.Page{{
+		ObjectId: "slide-1",
+		PageElements: []*slides.PageElement{
+			imageElement("image-1", "/missing.png"),
+			imageElement("image-2", "/ok.png"),
+		},
+	}}}
+	actions := []*action{{actionType: actionTypeUpdate, index: 0, slide: &Slide{}}}
+
+	for _, continueOnError := range []bool{false, true} {
+		d := &Deck{
+			logger:             slog.New(slog.NewJSONHandler(io.Discard, nil)),
+			presentation:       presentation,
+			continueOnImgError: continueOnError,
+		}
+		got, err := d.preloadCurrentImages(t.Context(), actions)
+		if err == nil {
+			t.Fatalf("continueOnImageError=%t: want error", continueOnError)
+		}
+		if !strings.Contains(err.Error(), ts.URL+"/missing.png") {
+			t.Errorf("continueOnImageError=%t: error should contain the URL: %v", continueOnError, err)
+		}
+		if !continueOnError {
+			if got != nil {
+				t.Errorf("got %v, want nil", got)
+			}
+			continue
+		}
+		images := got[0].currentImages
+		if len(images) != 2 {
+			t.Fatalf("got %d images, want 2", len(images))
+		}
+		if len(images[0].Bytes()) != 0 || !images[0].fromMarkdown || got[0].currentImageObjectIDMap[images[0]] != "image-1" {
+			t.Errorf("failed image should be preloaded without data: %+v", images[0])
+		}
+		if len(images[1].Bytes()) == 0 {
+			t.Error("fetched image should have data")
+		}
+		newImage, err := NewImageFromMarkdown("testdata/test.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if images[0].Equivalent(newImage) {
+			t.Error("failed image should not match any image")
+		}
+	}
+}
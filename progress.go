@@ -0,0 +1,48 @@
+package deck
+
+import "sync/atomic"
+
+// ProgressPhase represents a phase of applying slides.
+type ProgressPhase string
+
+const (
+	ProgressPhasePreloading ProgressPhase = "preloading" // fetching the images currently in the presentation
+	ProgressPhaseUploading  ProgressPhase = "uploading"  // uploading new images
+	ProgressPhaseBatching   ProgressPhase = "batching"   // sending batch update requests
+	ProgressPhaseRefreshing ProgressPhase = "refreshing" // fetching the presentation
+)
+
+// ProgressEvent represents the progress of a phase of applying slides.
+type ProgressEvent struct {
+	Phase   ProgressPhase
+	Current int
+	Total   int
+	// SlideIndex is the index of the slide the event relates to, or -1 if it does not relate to a slide.
+	SlideIndex int
+}
+
+// progress counts the progress of a phase and reports it to the callback set by WithProgress.
+type progress struct {
+	d       *Deck
+	phase   ProgressPhase
+	total   int
+	current atomic.Int64
+}
+
+func (d *Deck) newProgress(phase ProgressPhase, total int) *progress {
+	return &progress{d: d, phase: phase, total: total}
+}
+
+// done counts a unit of work as done and reports it. It is safe to call from multiple goroutines.
+func (p *progress) done(slideIndex int) {
+	current := int(p.current.Add(1))
+	if p.d.onProgress == nil {
+		return
+	}
+	p.d.onProgress(ProgressEvent{
+		Phase:      p.phase,
+		Current:    current,
+		Total:      p.total,
+		SlideIndex: slideIndex,
+	})
+}
@@ -0,0 +1,102 @@
+package deck
+
+import (
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type progressRecorder struct {
+	mu     sync.Mutex
+	events []ProgressEvent
+}
+
+func (r *progressRecorder) record(e ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *progressRecorder) phase(phase ProgressPhase) []ProgressEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []ProgressEvent
+	for _, e := range r.events {
+		if e.Phase == phase {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func TestProgressUploading(t *testing.T) {
+	png, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jpeg, err := NewImageFromMarkdown("testdata/test.jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &progressRecorder{}
+	d := &Deck{
+		logger:     slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		storage:    &countingStorage{},
+		onProgress: r.record,
+	}
+	actions := []*action{
+		{actionType: actionTypeAppend, index: 0, slide: &Slide{Images: []*Image{png, jpeg}}},
+	}
+
+	uploadedCh := d.startUploadingImages(t.Context(), actions, nil, nil)
+	if err := d.cleanupUploadedImages(t.Context(), uploadedCh); err != nil {
+		t.Fatal(err)
+	}
+
+	events := r.phase(ProgressPhaseUploading)
+	if len(events) != 2 {
+		t.Fatalf("got %d uploading events, want 2", len(events))
+	}
+	var currents []int
+	for _, e := range events {
+		if e.Total != 2 {
+			t.Errorf("got total %d, want 2", e.Total)
+		}
+		currents = append(currents, e.Current)
+	}
+	slices.Sort(currents)
+	if !slices.Equal(currents, []int{1, 2}) {
+		t.Errorf("got currents %v, want [1 2]", currents)
+	}
+}
+
+func TestProgressApply(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a", "b")}
+	r := &progressRecorder{}
+	d := newFakeDeck(t, s, WithProgress(r.record))
+
+	ss := Slides{{
+		Layout:      "Title and Content",
+		Titles:      []string{"a"},
+		TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "a"}}}}}},
+		Bodies:      []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "changed"}}}}}},
+	}}
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, phase := range []ProgressPhase{ProgressPhaseRefreshing, ProgressPhaseBatching} {
+		events := r.phase(phase)
+		if len(events) == 0 {
+			t.Errorf("got no %s events", phase)
+			continue
+		}
+		for _, e := range events {
+			if e.Current != e.Total || e.SlideIndex != -1 {
+				t.Errorf("unexpected %s event: %+v", phase, e)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package deck
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitTransport waits for a token of the limiter before sending each request.
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// transport wraps the base RoundTripper with the metrics, the rate limit and the request attribution of the Deck.
+func (d *Deck) transport(base http.RoundTripper) http.RoundTripper {
+	t := d.stats().transport(base)
+	if d.rateLimiter != nil {
+		t = &rateLimitTransport{base: t, limiter: d.rateLimiter}
+	}
+	return &attributionTransport{base: t}
+}
@@ -0,0 +1,43 @@
+package deck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	if err := WithRateLimit(0, 1)(&Deck{}); err == nil {
+		t.Error("expected error for zero rate but got none")
+	}
+	if err := WithRateLimit(1, 0)(&Deck{}); err == nil {
+		t.Error("expected error for zero burst but got none")
+	}
+
+	var calls int
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			_ = json.NewEncoder(w).Encode(&drive.File{Name: "title"})
+		},
+	}
+	d := newFakeDeck(t, s, WithRateLimit(0.1, 1))
+	if _, err := d.Title(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	// The burst is used up, so the next call has to wait about 10 seconds for a token.
+	d.title = ""
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := d.Title(ctx); err == nil {
+		t.Error("expected the call to be rate limited but got none")
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
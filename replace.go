@@ -0,0 +1,56 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+// ReplaceAllText replaces all occurrences of each key of replacements with its value throughout the presentation.
+// Keys are matched literally, so placeholders such as "{{client}}" are replaced as they are.
+// It returns the number of occurrences replaced, which is 0 in dry-run mode.
+func (d *Deck) ReplaceAllText(ctx context.Context, replacements map[string]string, matchCase bool) (_ int, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(replacements) == 0 {
+		return 0, nil
+	}
+	if _, ok := replacements[""]; ok {
+		return 0, fmt.Errorf("text to replace is empty")
+	}
+	var reqs []*slides.Request
+	for _, text := range slices.Sorted(maps.Keys(replacements)) {
+		reqs = append(reqs, &slides.Request{
+			ReplaceAllText: &slides.ReplaceAllTextRequest{
+				ContainsText: &slides.SubstringMatchCriteria{
+					Text:      text,
+					MatchCase: matchCase,
+				},
+				ReplaceText: replacements[text],
+			},
+		})
+	}
+	replies, err := d.batchUpdateWithReplies(ctx, reqs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace all text: %w", err)
+	}
+	var count int
+	for _, r := range replies {
+		if r.ReplaceAllText != nil {
+			count += int(r.ReplaceAllText.OccurrencesChanged)
+		}
+	}
+	if err := d.refresh(ctx); err != nil {
+		return 0, fmt.Errorf("failed to refresh presentation after replacing text: %w", err)
+	}
+	d.logger.Info("replaced all text", slog.Int("count", count))
+	return count, nil
+}
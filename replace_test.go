@@ -0,0 +1,53 @@
+package deck
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestReplaceAllText(t *testing.T) {
+	s := &fakeServer{
+		presentation: newFakePresentation("{{client}} report"),
+		batchUpdateReplies: func(req *slides.BatchUpdatePresentationRequest) []*slides.Response {
+			var replies []*slides.Response
+			for _, r := range req.Requests {
+				replies = append(replies, &slides.Response{ReplaceAllText: &slides.ReplaceAllTextResponse{
+					OccurrencesChanged: int64(len(r.ReplaceAllText.ContainsText.Text)),
+				}})
+			}
+			return replies
+		},
+	}
+	d := newFakeDeck(t, s)
+
+	count, err := d.ReplaceAllText(t.Context(), map[string]string{
+		"{{date}}":   "2025-01-01",
+		"{{client}}": "ACME",
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.batchUpdates) != 1 {
+		t.Fatalf("got %d batch updates, want 1", len(s.batchUpdates))
+	}
+	var got []string
+	for _, r := range s.batchUpdates[0].Requests {
+		c := r.ReplaceAllText.ContainsText
+		if !c.MatchCase {
+			t.Errorf("MatchCase of %q is false, want true", c.Text)
+		}
+		got = append(got, c.Text+"="+r.ReplaceAllText.ReplaceText)
+	}
+	if want := "{{client}}=ACME,{{date}}=2025-01-01"; strings.Join(got, ",") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ","), want)
+	}
+	if want := len("{{client}}") + len("{{date}}"); count != want {
+		t.Errorf("got count %d, want %d", count, want)
+	}
+
+	if _, err := d.ReplaceAllText(t.Context(), map[string]string{"": "x"}, false); err == nil {
+		t.Error("expected error for empty text but got none")
+	}
+}
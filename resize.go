@@ -0,0 +1,80 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// downscaleImage downscales the image so that its longest edge is at most maxDimension pixels,
+// preserving the aspect ratio and re-encoding it in the original MIME type.
+// The data is returned as is if the image is already small enough or is an animated GIF.
+func downscaleImage(data []byte, mimeType MIMEType, maxDimension int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	longest := max(cfg.Width, cfg.Height)
+	if maxDimension <= 0 || longest <= maxDimension {
+		return data, nil
+	}
+	if mimeType == MIMETypeImageGIF {
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode gif: %w", err)
+		}
+		if len(g.Image) > 1 {
+			return data, nil
+		}
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	width := max(1, cfg.Width*maxDimension/longest)
+	height := max(1, cfg.Height*maxDimension/longest)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	switch mimeType {
+	case MIMETypeImagePNG:
+		err = png.Encode(&buf, dst)
+	case MIMETypeImageJPEG:
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90})
+	case MIMETypeImageGIF:
+		err = gif.Encode(&buf, dst, nil)
+	default:
+		return nil, fmt.Errorf("unsupported image MIME type: %s", mimeType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// flattenGIF returns the first frame of an animated GIF as PNG.
+// ok is false if the GIF has a single frame, which is left as is.
+func flattenGIF(data []byte) (_ []byte, ok bool, err error) {
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode gif: %w", err)
+	}
+	if len(g.Image) < 2 {
+		return data, false, nil
+	}
+	// The first frame may cover only part of the logical screen.
+	dst := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	draw.Draw(dst, g.Image[0].Bounds(), g.Image[0], g.Image[0].Bounds().Min, draw.Over)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, false, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), true, nil
+}
@@ -0,0 +1,121 @@
+package deck
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestDownscaleImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	encode := func(mimeType MIMEType) []byte {
+		var buf bytes.Buffer
+		var err error
+		switch mimeType {
+		case MIMETypeImagePNG:
+			err = png.Encode(&buf, src)
+		case MIMETypeImageJPEG:
+			err = jpeg.Encode(&buf, src, nil)
+		case MIMETypeImageGIF:
+			err = gif.Encode(&buf, src, nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		mimeType     MIMEType
+		maxDimension int
+		wantWidth    int
+		wantHeight   int
+	}{
+		{MIMETypeImagePNG, 100, 100, 50},
+		{MIMETypeImageJPEG, 100, 100, 50},
+		{MIMETypeImageGIF, 100, 100, 50},
+		{MIMETypeImagePNG, 400, 400, 200},
+		{MIMETypeImagePNG, 1000, 400, 200},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mimeType), func(t *testing.T) {
+			got, err := downscaleImage(encode(tt.mimeType), tt.mimeType, tt.maxDimension)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(got))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if "image/"+format != string(tt.mimeType) {
+				t.Errorf("got format %q, want %q", format, tt.mimeType)
+			}
+			if cfg.Width != tt.wantWidth || cfg.Height != tt.wantHeight {
+				t.Errorf("got %dx%d, want %dx%d", cfg.Width, cfg.Height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+
+	t.Run("animated gif", func(t *testing.T) {
+		palette := color.Palette{color.Black, color.White}
+		frame := image.NewPaletted(image.Rect(0, 0, 400, 200), palette)
+		var buf bytes.Buffer
+		if err := gif.EncodeAll(&buf, &gif.GIF{Image: []*image.Paletted{frame, frame}, Delay: []int{0, 0}}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := downscaleImage(buf.Bytes(), MIMETypeImageGIF, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, buf.Bytes()) {
+			t.Error("animated gif should not be downscaled")
+		}
+	})
+}
+
+func TestFlattenGIF(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	first := image.NewPaletted(image.Rect(0, 0, 40, 20), palette)
+	first.SetColorIndex(0, 0, 1)
+	second := image.NewPaletted(image.Rect(0, 0, 40, 20), palette)
+	var animated bytes.Buffer
+	if err := gif.EncodeAll(&animated, &gif.GIF{Image: []*image.Paletted{first, second}, Delay: []int{0, 0}}); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := flattenGIF(animated.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("animated gif should be flattened")
+	}
+	img, format, err := image.Decode(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" {
+		t.Errorf("got format %q, want png", format)
+	}
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 20 {
+		t.Errorf("got %dx%d, want 40x20", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r != 0xffff {
+		t.Error("flattened image should be the first frame")
+	}
+
+	var still bytes.Buffer
+	if err := gif.Encode(&still, first, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err = flattenGIF(still.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok || !bytes.Equal(got, still.Bytes()) {
+		t.Error("single-frame gif should be left as is")
+	}
+}
@@ -0,0 +1,84 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Outcomes of slides in ApplyResult.
+const (
+	SlideOutcomeCreated = "created"
+	SlideOutcomeUpdated = "updated"
+	SlideOutcomeMoved   = "moved"
+	SlideOutcomeDeleted = "deleted"
+)
+
+// ApplyResult is the result of applying slides to the presentation.
+type ApplyResult struct {
+	Created        int            `json:"created"`
+	Updated        int            `json:"updated"`
+	Moved          int            `json:"moved"`
+	Deleted        int            `json:"deleted"`
+	ImagesUploaded int            `json:"images_uploaded"`
+	ImagesReused   int            `json:"images_reused"` // images that did not need uploading because an identical image was already available
+	Slides         []*SlideResult `json:"slides,omitempty"`
+	DryRun         bool           `json:"dry_run,omitempty"` // whether the changes are planned but not applied
+}
+
+// SlideResult is the outcome of a slide changed by apply.
+type SlideResult struct {
+	Outcome string   `json:"outcome"`
+	Index   int      `json:"index"` // index in the presentation before the slide is deleted, or after the slide is created, updated, or moved
+	Titles  []string `json:"titles,omitempty"`
+}
+
+// Changed returns the number of slides created, updated, moved, or deleted.
+func (r *ApplyResult) Changed() int {
+	return r.Created + r.Updated + r.Moved + r.Deleted
+}
+
+// String returns the changes of slides in a human-readable diff format.
+func (r *ApplyResult) String() string {
+	var b strings.Builder
+	for _, s := range r.Slides {
+		var mark string
+		switch s.Outcome {
+		case SlideOutcomeCreated:
+			mark = "+"
+		case SlideOutcomeUpdated:
+			mark = "~"
+		case SlideOutcomeMoved:
+			mark = ">"
+		case SlideOutcomeDeleted:
+			mark = "-"
+		}
+		fmt.Fprintf(&b, "%s %s page %d: %s\n", mark, s.Outcome, s.Index+1, strings.Join(s.Titles, ", "))
+	}
+	fmt.Fprintf(&b, "%d created, %d updated, %d moved, %d deleted\n", r.Created, r.Updated, r.Moved, r.Deleted)
+	return b.String()
+}
+
+func (r *ApplyResult) add(outcome string, index int, slide *Slide) {
+	switch outcome {
+	case SlideOutcomeCreated:
+		r.Created++
+	case SlideOutcomeUpdated:
+		r.Updated++
+	case SlideOutcomeMoved:
+		r.Moved++
+	case SlideOutcomeDeleted:
+		r.Deleted++
+	}
+	sr := &SlideResult{Outcome: outcome, Index: index}
+	if slide != nil {
+		sr.Titles = slide.Titles
+	}
+	r.Slides = append(r.Slides, sr)
+}
+
+// imageStats counts images uploaded and reused while applying slides.
+type imageStats struct {
+	uploaded atomic.Int64
+	reused   atomic.Int64
+}
@@ -17,6 +17,7 @@ type Slide struct {
 	BlockQuotes    []*BlockQuote `json:"block_quotes,omitempty"`
 	Tables         []*Table      `json:"tables,omitempty"`
 	SpeakerNote    string        `json:"speaker_note,omitempty"`
+	Accent         string        `json:"accent,omitempty"` // theme color (ACCENT1-6) of the title and rules
 
 	new    bool
 	delete bool
@@ -50,7 +51,17 @@ type BlockQuote struct {
 }
 
 type Table struct {
-	Rows []*TableRow `json:"rows,omitempty"`
+	Rows   []*TableRow         `json:"rows,omitempty"`
+	Style  *TableStyleOverride `json:"style,omitempty"`
+	Merges []*TableMerge       `json:"merges,omitempty"`
+}
+
+// TableMerge represents cells merged into the cell at Row and Column, spanning RowSpan rows and ColumnSpan columns.
+type TableMerge struct {
+	Row        int `json:"row"`
+	Column     int `json:"column"`
+	RowSpan    int `json:"row_span"`
+	ColumnSpan int `json:"column_span"`
 }
 
 type TableRow struct {
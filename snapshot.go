@@ -0,0 +1,158 @@
+package deck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+const mimeTypePPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+
+// snapshotsPath returns the path of the file to store the metadata of snapshots of the profile in.
+var snapshotsPath = func(profile string) string {
+	return filepath.Join(stateDir(profile), "snapshots.json")
+}
+
+// Snapshot is the metadata of a backup copy of a presentation.
+type Snapshot struct {
+	ID             string    `json:"id"`
+	PresentationID string    `json:"presentation_id"`
+	Title          string    `json:"title"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Snapshot copies the presentation to a backup file in the folder and returns the ID of the backup.
+// The metadata of the snapshot is stored so that it can be listed with Snapshots.
+func (d *Deck) Snapshot(ctx context.Context) (_ string, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.refresh(ctx); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	file := &drive.File{
+		Name:     fmt.Sprintf("%s (snapshot %s)", d.presentation.Title, now.Format(time.RFC3339)),
+		MimeType: "application/vnd.google-apps.presentation",
+	}
+	if d.folderID != "" {
+		file.Parents = []string{d.folderID}
+	}
+	var f *drive.File
+	if err := d.call(ctx, "copy presentation", func(ctx context.Context) (err error) {
+		f, err = d.driveSrv.Files.Copy(d.id, file).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to copy presentation: %w", err)
+	}
+	if err := addSnapshot(d.profile, &Snapshot{
+		ID:             f.Id,
+		PresentationID: d.id,
+		Title:          d.presentation.Title,
+		CreatedAt:      now,
+	}); err != nil {
+		return "", err
+	}
+	d.logger.Info("created snapshot", slog.String("snapshot_id", f.Id))
+	return f.Id, nil
+}
+
+// Restore overwrites the presentation with the content of the snapshot.
+// The content is transferred as PowerPoint, so the ID of the presentation is kept
+// but features that PowerPoint does not support may be lost.
+func (d *Deck) Restore(ctx context.Context, snapshotID string) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b []byte
+	if err := d.call(ctx, "export snapshot", func(ctx context.Context) error {
+		res, err := d.driveSrv.Files.Export(snapshotID, mimeTypePPTX).Context(ctx).Download()
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		b, err = io.ReadAll(res.Body)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to export snapshot %s: %w", snapshotID, err)
+	}
+	if err := d.call(ctx, "restore presentation", func(ctx context.Context) error {
+		_, err := d.driveSrv.Files.Update(d.id, &drive.File{}).
+			Media(bytes.NewReader(b), googleapi.ContentType(mimeTypePPTX)).SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to restore presentation from snapshot %s: %w", snapshotID, err)
+	}
+	d.fresh = false
+	d.logger.Info("restored presentation", slog.String("snapshot_id", snapshotID))
+	return d.refresh(ctx)
+}
+
+// Snapshots returns the snapshots of the presentation, newest first.
+func (d *Deck) Snapshots() (_ []*Snapshot, err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	all, err := loadSnapshots(d.profile)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*Snapshot
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].PresentationID == d.id {
+			snapshots = append(snapshots, all[i])
+		}
+	}
+	return snapshots, nil
+}
+
+func loadSnapshots(profile string) ([]*Snapshot, error) {
+	b, err := os.ReadFile(snapshotsPath(profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	var snapshots []*Snapshot
+	if err := json.Unmarshal(b, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+func addSnapshot(profile string, s *Snapshot) error {
+	snapshots, err := loadSnapshots(profile)
+	if err != nil {
+		return err
+	}
+	snapshots = append(snapshots, s)
+	b, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	p := snapshotsPath(profile)
+	if err := mkdirState(filepath.Dir(p)); err != nil {
+		return fmt.Errorf("failed to create directory for snapshots: %w", err)
+	}
+	if err := os.WriteFile(p, b, 0600); err != nil {
+		return fmt.Errorf("failed to write snapshots: %w", err)
+	}
+	return nil
+}
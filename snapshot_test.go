@@ -0,0 +1,76 @@
+package deck
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestSnapshotAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.json")
+	orig := snapshotsPath
+	snapshotsPath = func(profile string) string { return path }
+	t.Cleanup(func() { snapshotsPath = orig })
+
+	var (
+		copied   *drive.File
+		restored []byte
+	)
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/files/fake-presentation/copy"):
+				copied = &drive.File{}
+				if err := json.NewDecoder(r.Body).Decode(copied); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(&drive.File{Id: "snapshot-1"})
+			case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/files/snapshot-1/export"):
+				_, _ = w.Write([]byte("pptx"))
+			case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/files/fake-presentation"):
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				restored = b
+				_ = json.NewEncoder(w).Encode(&drive.File{Id: "fake-presentation"})
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	}
+	d := newFakeDeck(t, s, WithFolderID("backup"))
+
+	id, err := d.Snapshot(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "snapshot-1" {
+		t.Errorf("got snapshot ID %q, want %q", id, "snapshot-1")
+	}
+	if copied == nil || len(copied.Parents) != 1 || copied.Parents[0] != "backup" {
+		t.Errorf("snapshot should be copied to the folder: %+v", copied)
+	}
+	snapshots, err := d.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != "snapshot-1" || snapshots[0].PresentationID != "fake-presentation" {
+		t.Errorf("unexpected snapshots: %+v", snapshots)
+	}
+
+	if err := d.Restore(t.Context(), id); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(restored), "pptx") {
+		t.Errorf("presentation should be overwritten with the exported snapshot: %q", restored)
+	}
+}
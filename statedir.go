@@ -0,0 +1,56 @@
+package deck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/k1LoW/deck/config"
+)
+
+// EnvStateDirMode - Permission bits in octal of the directories created in the state directory (default: 0700).
+// Example: DECK_STATE_DIR_MODE=0750.
+const EnvStateDirMode = "DECK_STATE_DIR_MODE"
+
+const defaultStateDirMode os.FileMode = 0700
+
+// stateDirMode returns the permission bits of the directories created in the state directory.
+// The owner must be able to use the directories, so modes without rwx for the owner are rejected.
+func stateDirMode() (os.FileMode, error) {
+	v := os.Getenv(EnvStateDirMode)
+	if v == "" {
+		return defaultStateDirMode, nil
+	}
+	m, err := strconv.ParseUint(v, 8, 32)
+	if err != nil || m > 0777 {
+		return 0, fmt.Errorf("invalid %s: %q must be permission bits in octal (e.g., 0750)", EnvStateDirMode, v)
+	}
+	mode := os.FileMode(m)
+	if mode&0700 != 0700 {
+		return 0, fmt.Errorf("invalid %s: %q must allow the owner to read, write and search (0700)", EnvStateDirMode, v)
+	}
+	return mode, nil
+}
+
+// stateHome returns the state home directory. It is a variable to be replaced in tests.
+var stateHome = config.StateHomePath
+
+// stateDir returns the directory to store the state of the profile in, such as the token, the image cache and snapshots.
+// The state of the default profile is stored in the state home, and that of a named profile in profiles/{profile} of it,
+// so that profiles for different Google accounts never share state.
+func stateDir(profile string) string {
+	if profile == "" {
+		return stateHome()
+	}
+	return filepath.Join(stateHome(), "profiles", profile)
+}
+
+// mkdirState creates the directory in the state directory with the mode of stateDirMode.
+func mkdirState(dir string) error {
+	mode, err := stateDirMode()
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, mode)
+}
@@ -0,0 +1,88 @@
+package deck
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestStateDirMode(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    os.FileMode
+		wantErr bool
+	}{
+		{"", 0700, false},
+		{"0750", 0750, false},
+		{"755", 0755, false},
+		{"0640", 0, true},
+		{"1777", 0, true},
+		{"rwx", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(EnvStateDirMode, tt.env)
+			got, err := stateDirMode()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("stateDirMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("stateDirMode() = %o, want %o", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfilesDoNotShareTokens(t *testing.T) {
+	home := t.TempDir()
+	orig := stateHome
+	stateHome = func() string { return home }
+	t.Cleanup(func() { stateHome = orig })
+	newProfileDeck := func(profile string) (*Deck, string) {
+		t.Helper()
+		if err := mkdirState(stateDir(profile)); err != nil {
+			t.Fatal(err)
+		}
+		return &Deck{profile: profile, logger: slog.New(slog.NewJSONHandler(io.Discard, nil))}, filepath.Join(stateDir(profile), "token.json")
+	}
+	a, aPath := newProfileDeck("a")
+	b, bPath := newProfileDeck("b")
+	def, defPath := newProfileDeck("")
+
+	if err := a.saveToken(aPath, &oauth2.Token{AccessToken: "token-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := a.loadToken(aPath); err != nil || got.AccessToken != "token-a" {
+		t.Errorf("profile a: got %v, %v, want token-a", got, err)
+	}
+	for _, tt := range []struct {
+		d    *Deck
+		path string
+	}{{b, bPath}, {def, defPath}} {
+		if got, err := tt.d.loadToken(tt.path); err == nil {
+			t.Errorf("profile %q read the token of another profile: %v", tt.d.profile, got)
+		}
+	}
+
+	// A token of an older version is moved to the directory of the profile.
+	legacyPath := filepath.Join(home, "token-b.json")
+	if err := b.saveToken(legacyPath, &oauth2.Token{AccessToken: "token-b"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := b.loadToken(bPath); err != nil || got.AccessToken != "token-b" {
+		t.Errorf("profile b: got %v, %v, want token-b", got, err)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Errorf("old token file should be removed: %v", err)
+	}
+	if got, err := b.tokenFromFile(bPath); err != nil || got.AccessToken != "token-b" {
+		t.Errorf("profile b: got %v, %v, want token-b in %s", got, err, bPath)
+	}
+	if got, err := a.loadToken(aPath); err != nil || got.AccessToken != "token-a" {
+		t.Errorf("profile a: got %v, %v, want token-a", got, err)
+	}
+}
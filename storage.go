@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/k1LoW/deck/template"
@@ -15,6 +17,9 @@ import (
 	"google.golang.org/api/drive/v3"
 )
 
+// assetsFolderName is the name of the folder that permanent images are uploaded to.
+const assetsFolderName = "deck-assets"
+
 // Storage is the interface for image upload/delete operations.
 type Storage interface {
 	Upload(ctx context.Context, data []byte, mimeType string) (publicURL, uploadedID string, err error)
@@ -27,6 +32,18 @@ type googleDriveStorage struct {
 	folderID             string
 	allowReadingByAnyone func(ctx context.Context, fileID string) error
 	deleteOrTrash        func(ctx context.Context, fileID string) error
+
+	// contentAddressed names uploaded files by the SHA-256 of the image data,
+	// so that identical images are uploaded once and shared.
+	contentAddressed bool
+	// locks guards against concurrent upload of the same content. key: file name, value: *sync.Mutex
+	locks *sync.Map
+	// requestTimeout is the timeout for each call to the Google Drive API.
+	requestTimeout time.Duration
+	// assetsFolderName is the name of the subfolder of folderID to upload images to, if set.
+	assetsFolderName string
+	assetsFolderMu   sync.Mutex
+	assetsFolderID   string
 }
 
 // newGoogleDriveStorage creates a new googleDriveStorage.
@@ -45,17 +62,43 @@ func newGoogleDriveStorage(
 }
 
 // Upload uploads an image to Google Drive.
+// If the storage is content-addressed and a file with the same content exists, it is reused.
 func (u *googleDriveStorage) Upload(ctx context.Context, data []byte, mimeType string) (publicURL, uploadedID string, err error) {
+	folderID, err := u.uploadFolderID(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	name := tempFileName(data)
+	if u.contentAddressed {
+		name = contentAddressedFileName(data)
+		if u.locks != nil {
+			v, _ := u.locks.LoadOrStore(name, &sync.Mutex{})
+			mu := v.(*sync.Mutex)
+			mu.Lock()
+			defer mu.Unlock()
+		}
+		existing, err := u.findFile(ctx, folderID, name)
+		if err != nil {
+			return "", "", err
+		}
+		if existing != nil {
+			return existing.WebContentLink, existing.Id, nil
+		}
+	}
 	df := &drive.File{
-		Name:     fmt.Sprintf("________tmp-for-deck-%s", time.Now().Format(time.RFC3339)),
+		Name:     name,
 		MimeType: mimeType,
 	}
-	if u.folderID != "" {
-		df.Parents = []string{u.folderID}
+	if folderID != "" {
+		df.Parents = []string{folderID}
 	}
 
-	uploaded, err := u.driveSrv.Files.Create(df).Media(bytes.NewBuffer(data)).SupportsAllDrives(true).Do()
-	if err != nil {
+	var uploaded *drive.File
+	if err := callWithTimeout(ctx, u.requestTimeout, "upload image", func(ctx context.Context) (err error) {
+		uploaded, err = u.driveSrv.Files.Create(df).Media(bytes.NewBuffer(data)).Fields("id", "webContentLink").
+			SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
 		return "", "", fmt.Errorf("failed to upload image: %w", err)
 	}
 	uploadedID = uploaded.Id
@@ -75,25 +118,119 @@ func (u *googleDriveStorage) Upload(ctx context.Context, data []byte, mimeType s
 		return "", "", fmt.Errorf("failed to set permission for image: %w", err)
 	}
 
-	// Get webContentLink
-	f, err := u.driveSrv.Files.Get(uploaded.Id).Fields("webContentLink").SupportsAllDrives(true).Do()
-	if err != nil {
-		return "", "", fmt.Errorf("failed to get webContentLink for image: %w", err)
+	// The webContentLink is returned by the create call, so it is fetched only if it was not.
+	publicURL = uploaded.WebContentLink
+	if publicURL == "" {
+		var f *drive.File
+		if err = callWithTimeout(ctx, u.requestTimeout, "get webContentLink", func(ctx context.Context) (err error) {
+			f, err = u.driveSrv.Files.Get(uploaded.Id).Fields("webContentLink").SupportsAllDrives(true).Context(ctx).Do()
+			return err
+		}); err != nil {
+			return "", "", fmt.Errorf("failed to get webContentLink for image: %w", err)
+		}
+		publicURL = f.WebContentLink
 	}
-
-	if f.WebContentLink == "" {
+	if publicURL == "" {
 		return "", "", fmt.Errorf("webContentLink is empty for image: %s", uploaded.Id)
 	}
-	publicURL = f.WebContentLink
 
 	return publicURL, uploadedID, nil
 }
 
+// uploadFolderID returns the ID of the folder to upload images to.
+// If the assets folder is set, it is looked up in the folder, and created if it does not exist.
+func (u *googleDriveStorage) uploadFolderID(ctx context.Context) (string, error) {
+	if u.assetsFolderName == "" {
+		return u.folderID, nil
+	}
+	u.assetsFolderMu.Lock()
+	defer u.assetsFolderMu.Unlock()
+	if u.assetsFolderID != "" {
+		return u.assetsFolderID, nil
+	}
+	parentID := u.folderID
+	if parentID == "" {
+		parentID = "root"
+	}
+	q := fmt.Sprintf("name = '%s' and mimeType = '%s' and '%s' in parents and trashed = false",
+		u.assetsFolderName, mimeTypeFolder, parentID)
+	var list *drive.FileList
+	if err := callWithTimeout(ctx, u.requestTimeout, "find assets folder", func(ctx context.Context) (err error) {
+		list, err = u.driveSrv.Files.List().Q(q).Fields("files(id)").
+			SupportsAllDrives(true).IncludeItemsFromAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to find assets folder %s: %w", u.assetsFolderName, err)
+	}
+	if len(list.Files) > 0 {
+		u.assetsFolderID = list.Files[0].Id
+		return u.assetsFolderID, nil
+	}
+	folder := &drive.File{
+		Name:     u.assetsFolderName,
+		MimeType: mimeTypeFolder,
+		Parents:  []string{parentID},
+	}
+	var created *drive.File
+	if err := callWithTimeout(ctx, u.requestTimeout, "create assets folder", func(ctx context.Context) (err error) {
+		created, err = u.driveSrv.Files.Create(folder).Fields("id").SupportsAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to create assets folder %s: %w", u.assetsFolderName, err)
+	}
+	u.assetsFolderID = created.Id
+	return u.assetsFolderID, nil
+}
+
+// findFile returns the file with the name and a webContentLink in the folder, or nil if there is no such file.
+func (u *googleDriveStorage) findFile(ctx context.Context, folderID, name string) (*drive.File, error) {
+	q := fmt.Sprintf("name = '%s' and trashed = false", name)
+	if folderID != "" {
+		q += fmt.Sprintf(" and '%s' in parents", folderID)
+	}
+	var list *drive.FileList
+	if err := callWithTimeout(ctx, u.requestTimeout, "find uploaded image", func(ctx context.Context) (err error) {
+		list, err = u.driveSrv.Files.List().Q(q).Fields("files(id, webContentLink)").
+			SupportsAllDrives(true).IncludeItemsFromAllDrives(true).Context(ctx).Do()
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to find uploaded image %s: %w", name, err)
+	}
+	for _, f := range list.Files {
+		if f.WebContentLink != "" {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// tempFilePrefix is the prefix of the names of temporary files uploaded to Google Drive.
+const tempFilePrefix = "________tmp-for-deck-"
+
+// tempFileSeq is the sequence number of temporary files uploaded by this process.
+var tempFileSeq atomic.Uint64
+
+// tempFileName returns a unique name of the temporary file to upload the image data to.
+// The name consists of the upload time, a sequence number and a short hash of the data,
+// so images uploaded within the same second do not share a name.
+func tempFileName(data []byte) string {
+	return fmt.Sprintf("%s%s-%d-%s", tempFilePrefix,
+		time.Now().Format(time.RFC3339), tempFileSeq.Add(1), hashHex(data)[:12])
+}
+
+// contentAddressedFileName returns the name of the file to upload the image data to, keyed by its SHA-256.
+func contentAddressedFileName(data []byte) string {
+	return "deck-image-" + hashHex(data)
+}
+
 // Delete deletes an uploaded image from Google Drive.
 func (u *googleDriveStorage) Delete(ctx context.Context, uploadedID string) error {
 	return u.deleteOrTrash(ctx, uploadedID)
 }
 
+// defaultUploadCacheControl is the Cache-Control passed to the upload command when DECK_UPLOAD_CACHE_CONTROL is not set.
+const defaultUploadCacheControl = "public, max-age=3600"
+
 // externalStorage implements Storage using external CLI commands.
 type externalStorage struct {
 	uploadCmd string
@@ -109,20 +246,32 @@ func newExternalStorage(uploadCmd, deleteCmd string) *externalStorage {
 }
 
 // Upload uploads an image using the external upload command.
-// It passes image data via stdin and sets the environment variable DECK_UPLOAD_MIME.
-// The command also supports template variables: {{mime}} and {{env.XXX}}.
+// It passes image data via stdin and sets the environment variables DECK_UPLOAD_MIME and DECK_UPLOAD_CACHE_CONTROL.
+// DECK_UPLOAD_CACHE_CONTROL is the Cache-Control to set on the uploaded object. It defaults to "public, max-age=3600"
+// and can be overridden by setting the environment variable.
+// The command also supports template variables: {{mime}}, {{cacheControl}} and {{env.XXX}}.
 // The command should output the public URL on the first line and uploaded ID on the second line.
 func (u *externalStorage) Upload(ctx context.Context, data []byte, mimeType string) (publicURL, uploadedID string, err error) {
-	const envUploadMIME = "DECK_UPLOAD_MIME"
+	const (
+		envUploadMIME         = "DECK_UPLOAD_MIME"
+		envUploadCacheControl = "DECK_UPLOAD_CACHE_CONTROL"
+	)
+
+	cacheControl := os.Getenv(envUploadCacheControl)
+	if cacheControl == "" {
+		cacheControl = defaultUploadCacheControl
+	}
 
 	// Prepare environment variables
 	env := template.EnvironToMap()
 	env[envUploadMIME] = mimeType
+	env[envUploadCacheControl] = cacheControl
 
 	// Prepare template store
 	store := map[string]any{
-		"mime": mimeType,
-		"env":  env,
+		"mime":         mimeType,
+		"cacheControl": cacheControl,
+		"env":          env,
 	}
 
 	// Expand template in command
@@ -139,7 +288,7 @@ func (u *externalStorage) Upload(ctx context.Context, data []byte, mimeType stri
 	cmd := exec.CommandContext(ctx, c, args...)
 	cmd.Stdin = bytes.NewReader(data)
 	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, envUploadMIME+"="+mimeType)
+	cmd.Env = append(cmd.Env, envUploadMIME+"="+mimeType, envUploadCacheControl+"="+cacheControl)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
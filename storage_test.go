@@ -0,0 +1,346 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestExternalStorageUploadCacheControl(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{"default", "", defaultUploadCacheControl},
+		{"override by env", "no-cache", "no-cache"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DECK_UPLOAD_CACHE_CONTROL", tt.env)
+			s := newExternalStorage(`echo "$DECK_UPLOAD_CACHE_CONTROL"; echo "{{cacheControl}}"`, "")
+			publicURL, uploadedID, err := s.Upload(t.Context(), []byte("data"), "image/png")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if publicURL != tt.want {
+				t.Errorf("got env %q, want %q", publicURL, tt.want)
+			}
+			if uploadedID != tt.want {
+				t.Errorf("got template variable %q, want %q", uploadedID, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoogleDriveStorageContentAddressed(t *testing.T) {
+	var (
+		files   = map[string]*drive.File{} // key: file ID
+		creates int
+	)
+	s := &fakeServer{
+		presentation: newFakePresentation(),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/upload/drive/v3/files"):
+				creates++
+				mr, err := multipartReader(r)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				f := &drive.File{}
+				if err := json.NewDecoder(mr).Decode(f); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				f.Id = fmt.Sprintf("file-%d", creates)
+				f.WebContentLink = "https://example.com/" + f.Id
+				files[f.Id] = f
+				_ = json.NewEncoder(w).Encode(f)
+			case r.Method == http.MethodGet && r.URL.Path == "/drive/v3/files":
+				list := &drive.FileList{}
+				for _, f := range files {
+					if strings.Contains(r.URL.Query().Get("q"), "'"+f.Name+"'") {
+						list.Files = append(list.Files, f)
+					}
+				}
+				_ = json.NewEncoder(w).Encode(list)
+			case strings.HasSuffix(r.URL.Path, "/permissions"):
+				_ = json.NewEncoder(w).Encode(&drive.Permission{})
+			case r.Method == http.MethodGet:
+				f, ok := files[strings.TrimPrefix(r.URL.Path, "/drive/v3/files/")]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(f)
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	}
+	d := newFakeDeck(t, s, WithContentAddressedImages(true))
+	storage := d.getStorage()
+
+	url1, id1, err := storage.Upload(t.Context(), []byte("image"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url2, id2, err := storage.Upload(t.Context(), []byte("image"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creates != 1 {
+		t.Errorf("got %d uploads, want 1", creates)
+	}
+	if url1 != url2 || id1 != id2 {
+		t.Errorf("second identical image should reuse the existing object: %s, %s", id1, id2)
+	}
+	if got := files[id1].Name; got != contentAddressedFileName([]byte("image")) {
+		t.Errorf("got file name %q", got)
+	}
+	if _, _, err := storage.Upload(t.Context(), []byte("other"), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	if creates != 2 {
+		t.Errorf("got %d uploads, want 2", creates)
+	}
+	if !d.keepsUploadedImages() {
+		t.Error("content-addressed images should not be cleaned up")
+	}
+}
+
+func TestGoogleDriveStorageUploadWebContentLink(t *testing.T) {
+	for _, linkOnCreate := range []bool{true, false} {
+		var (
+			fields string
+			gets   int
+		)
+		s := &fakeServer{
+			presentation: newFakePresentation(),
+			driveHandler: func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/upload/drive/v3/files"):
+					fields = r.URL.Query().Get("fields")
+					f := &drive.File{Id: "file-1"}
+					if linkOnCreate {
+						f.WebContentLink = "https://example.com/file-1"
+					}
+					_ = json.NewEncoder(w).Encode(f)
+				case strings.HasSuffix(r.URL.Path, "/permissions"):
+					_ = json.NewEncoder(w).Encode(&drive.Permission{})
+				case r.Method == http.MethodGet && r.URL.Path == "/drive/v3/files/file-1":
+					gets++
+					_ = json.NewEncoder(w).Encode(&drive.File{WebContentLink: "https://example.com/file-1"})
+				default:
+					http.NotFound(w, r)
+				}
+			},
+		}
+		d := newFakeDeck(t, s)
+
+		url, id, err := d.getStorage().Upload(t.Context(), []byte("image"), "image/png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != "https://example.com/file-1" || id != "file-1" {
+			t.Errorf("linkOnCreate=%t: got %s, %s", linkOnCreate, url, id)
+		}
+		if !strings.Contains(fields, "webContentLink") {
+			t.Errorf("linkOnCreate=%t: webContentLink should be requested on create: fields=%q", linkOnCreate, fields)
+		}
+		want := 1
+		if linkOnCreate {
+			want = 0
+		}
+		if gets != want {
+			t.Errorf("linkOnCreate=%t: got %d gets, want %d", linkOnCreate, gets, want)
+		}
+	}
+}
+
+// multipartReader returns the reader of the metadata part of a multipart upload request.
+func multipartReader(r *http.Request) (io.Reader, error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	return multipart.NewReader(r.Body, params["boundary"]).NextPart()
+}
+
+func TestApplyWithPermanentImages(t *testing.T) {
+	var (
+		files   = map[string]*drive.File{} // key: file ID
+		deletes int
+	)
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/upload/drive/v3/files":
+				mr, err := multipartReader(r)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				f := &drive.File{}
+				if err := json.NewDecoder(mr).Decode(f); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				f.Id = fmt.Sprintf("file-%d", len(files))
+				f.WebContentLink = "https://example.com/" + f.Id
+				files[f.Id] = f
+				_ = json.NewEncoder(w).Encode(f)
+			case r.Method == http.MethodPost && r.URL.Path == "/drive/v3/files":
+				f := &drive.File{}
+				if err := json.NewDecoder(r.Body).Decode(f); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				f.Id = fmt.Sprintf("folder-%d", len(files))
+				files[f.Id] = f
+				_ = json.NewEncoder(w).Encode(f)
+			case r.Method == http.MethodGet && r.URL.Path == "/drive/v3/files":
+				list := &drive.FileList{}
+				for _, f := range files {
+					if strings.Contains(r.URL.Query().Get("q"), "'"+f.Name+"'") {
+						list.Files = append(list.Files, f)
+					}
+				}
+				_ = json.NewEncoder(w).Encode(list)
+			case strings.HasSuffix(r.URL.Path, "/permissions"):
+				_ = json.NewEncoder(w).Encode(&drive.Permission{})
+			case r.Method == http.MethodDelete:
+				deletes++
+				w.WriteHeader(http.StatusNoContent)
+			case r.Method == http.MethodGet:
+				f, ok := files[strings.TrimPrefix(r.URL.Path, "/drive/v3/files/")]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(f)
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	}
+	d := newFakeDeck(t, s, WithPermanentImages(true), WithFolderID("parent"))
+	image, err := NewImageFromMarkdown("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Apply(t.Context(), Slides{{Layout: "Title and Content", Titles: []string{"a"}, Images: []*Image{image}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if deletes != 0 {
+		t.Errorf("got %d deletes, want permanent images to be retained", deletes)
+	}
+	var folder, uploaded *drive.File
+	for _, f := range files {
+		if f.MimeType == mimeTypeFolder {
+			folder = f
+		} else {
+			uploaded = f
+		}
+	}
+	if folder == nil || folder.Name != assetsFolderName || !slices.Equal(folder.Parents, []string{"parent"}) {
+		t.Fatalf("got assets folder %+v, want %q in the folder", folder, assetsFolderName)
+	}
+	if uploaded == nil || !slices.Equal(uploaded.Parents, []string{folder.Id}) {
+		t.Fatalf("got uploaded image %+v, want it in the assets folder", uploaded)
+	}
+}
+
+type closingStorage struct {
+	countingStorage
+	closed int
+}
+
+func (s *closingStorage) Close() error {
+	s.closed++
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	s := &closingStorage{}
+	d := &Deck{storage: s}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s.closed != 1 {
+		t.Errorf("got %d closes, want 1", s.closed)
+	}
+
+	// Closing again or concurrently closes the storage only once.
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if s.closed != 1 {
+		t.Errorf("got %d closes, want 1", s.closed)
+	}
+
+	// The storages in an unused chain are closed without building the fallback storage.
+	chained := &closingStorage{}
+	d = &Deck{storageChain: []Storage{chained}}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if chained.closed != 1 || d.fallbackStorage != nil {
+		t.Errorf("got %d closes and fallback storage %v, want 1 close and no fallback storage", chained.closed, d.fallbackStorage)
+	}
+
+	// A storage shared with sibling decks is left to the caller.
+	shared := &closingStorage{}
+	sibling, err := (&Deck{}).sibling(WithStorage(shared), WithStorageChain(shared))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sibling.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if shared.closed != 0 {
+		t.Errorf("got %d closes of the shared storage, want 0", shared.closed)
+	}
+
+	// A storage that is not an io.Closer is left as it is.
+	if err := (&Deck{storage: &countingStorage{}}).Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTempFileName(t *testing.T) {
+	data := []byte("image")
+	a, b := tempFileName(data), tempFileName(data)
+	if a == b {
+		t.Errorf("got the same name %q for two uploads", a)
+	}
+	for _, name := range []string{a, b} {
+		if !strings.HasPrefix(name, "________tmp-for-deck-") {
+			t.Errorf("got %q, want the prefix of temporary files", name)
+		}
+		if !strings.HasSuffix(name, hashHex(data)[:12]) {
+			t.Errorf("got %q, want the hash of the data", name)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// isSVG reports whether the data looks like an SVG document.
+func isSVG(b []byte) bool {
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	b = bytes.TrimSpace(b)
+	if !bytes.HasPrefix(b, []byte("<")) {
+		return false
+	}
+	head := b[:min(len(b), 1024)]
+	return bytes.Contains(head, []byte("<svg"))
+}
+
+// rasterizeSVG rasterizes the SVG to PNG.
+// The size is taken from the viewBox of the SVG (or its width and height) and multiplied by scale.
+func rasterizeSVG(b []byte, scale float64) ([]byte, error) {
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse svg: %w", err)
+	}
+	w := int(math.Ceil(icon.ViewBox.W * scale))
+	h := int(math.Ceil(icon.ViewBox.H * scale))
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("invalid svg size: %vx%v", icon.ViewBox.W, icon.ViewBox.H)
+	}
+	icon.SetTarget(0, 0, float64(w), float64(h))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	scanner := rasterx.NewScannerGV(w, h, img, img.Bounds())
+	icon.Draw(rasterx.NewDasher(w, h, scanner), 1)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode rasterized svg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+package deck
+
+import (
+	"bytes"
+	"image"
+	"os"
+	"testing"
+)
+
+func TestIsSVG(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{`<svg xmlns="http://www.w3.org/2000/svg"></svg>`, true},
+		{"\xef\xbb\xbf<?xml version=\"1.0\"?>\n<svg></svg>", true},
+		{"\n  <!-- comment -->\n<svg></svg>", true},
+		{"\x89PNG\r\n\x1a\n", false},
+		{"<html></html>", false},
+	}
+	for _, tt := range tests {
+		if got := isSVG([]byte(tt.in)); got != tt.want {
+			t.Errorf("isSVG(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRasterizeSVG(t *testing.T) {
+	b, err := os.ReadFile("testdata/test.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		scale  float64
+		width  int
+		height int
+	}{
+		{1, 40, 20},
+		{2.5, 100, 50},
+	} {
+		got, err := rasterizeSVG(b, tt.scale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, format, err := image.Decode(bytes.NewReader(got))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != "png" {
+			t.Errorf("got format %q, want png", format)
+		}
+		if img.Bounds().Dx() != tt.width || img.Bounds().Dy() != tt.height {
+			t.Errorf("scale %v: got %dx%d, want %dx%d", tt.scale, img.Bounds().Dx(), img.Bounds().Dy(), tt.width, tt.height)
+		}
+		if r, _, _, _ := img.At(1, 1).RGBA(); r>>8 != 0xff {
+			t.Errorf("scale %v: svg is not drawn", tt.scale)
+		}
+	}
+}
+
+func TestNewImageRasterizesSVG(t *testing.T) {
+	i, err := NewImage("testdata/test.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.mimeType != MIMETypeImagePNG {
+		t.Errorf("got MIME type %q, want %q", i.mimeType, MIMETypeImagePNG)
+	}
+	if i.svg == nil {
+		t.Error("original svg should be kept to rasterize at another scale")
+	}
+	if !i.IsUploadNeeded() {
+		t.Error("rasterized image should be uploaded")
+	}
+}
+
+func TestApplyRasterizesSVGWithScale(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	storage := &countingStorage{}
+	d := newFakeDeck(t, s, WithStorage(storage), WithSVGScale(2.5))
+	svg, err := NewImage("testdata/test.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss := Slides{{Layout: "Title and Content", Titles: []string{"a"}, Images: []*Image{svg}}}
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+	if len(storage.data) != 1 {
+		t.Fatalf("got %d uploads, want 1", len(storage.data))
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(storage.data[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 100 || cfg.Height != 50 {
+		t.Errorf("got %dx%d, want 100x50", cfg.Width, cfg.Height)
+	}
+}
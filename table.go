@@ -27,6 +27,7 @@ func (d *Deck) handleTableUpdates(slideObjectID string, newTables []*Table, curr
 	for _, element := range deckTables {
 		table := convertSlidesToTable(element.Table)
 		if table != nil {
+			table.Style = readTableStyleOverride(element)
 			existingTables = append(existingTables, table)
 		}
 	}
@@ -112,6 +113,20 @@ func (d *Deck) reuseTableRequests(existingElement *slides.PageElement, newTable
 		}
 	}
 
+	// Unmerge all cells so that rows and columns can be adjusted, and merge them again at the end
+	if hasTableMerges(existingTable) {
+		requests = append(requests, &slides.Request{
+			UnmergeTableCells: &slides.UnmergeTableCellsRequest{
+				ObjectId: tableObjectID,
+				TableRange: &slides.TableRange{
+					Location:   &slides.TableCellLocation{},
+					RowSpan:    int64(existingRows),
+					ColumnSpan: int64(existingCols),
+				},
+			},
+		})
+	}
+
 	// Clear all existing text content
 	for rowIdx, row := range existingTable.TableRows {
 		if row == nil {
@@ -199,6 +214,18 @@ func (d *Deck) reuseTableRequests(existingElement *slides.PageElement, newTable
 		}
 	}
 
+	requests = append(requests, tableMergeRequests(tableObjectID, newTable)...)
+
+	// Store the style override of the new table, clearing the one of the existing table
+	requests = append(requests, &slides.Request{
+		UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+			ObjectId:        tableObjectID,
+			Title:           newTable.Style.altTextTitle(),
+			Description:     descriptionTableFromMarkdown,
+			ForceSendFields: []string{"Title"},
+		},
+	})
+
 	return requests, nil
 }
 
@@ -282,6 +309,21 @@ func (d *Deck) createTableStructureRequest(slideObjectID string, table *Table, i
 		return nil, nil
 	}
 
+	width := float64(cols * defaultTableColumnEMU) // 1,000,000 EMU per column
+	translateX := float64(index * 500000)          // offset tables to avoid overlap
+	// Fit tables that would overflow the slide to the content area
+	var columnWidths []float64
+	if d.presentation != nil && d.presentation.PageSize != nil && d.presentation.PageSize.Width != nil {
+		columnWidths = fitTableColumnWidths(table, d.presentation.PageSize.Width.Magnitude-2*tableMargin)
+	}
+	if columnWidths != nil {
+		width = 0
+		for _, w := range columnWidths {
+			width += w
+		}
+		translateX = tableMargin
+	}
+
 	// Create table request
 	createTableReq := &slides.CreateTableRequest{
 		ObjectId: tableObjectID,
@@ -293,14 +335,14 @@ func (d *Deck) createTableStructureRequest(slideObjectID string, table *Table, i
 					Unit:      "EMU",
 				},
 				Width: &slides.Dimension{
-					Magnitude: float64(cols * 1000000), // 1,000,000 EMU per column
+					Magnitude: width,
 					Unit:      "EMU",
 				},
 			},
 			Transform: &slides.AffineTransform{
 				ScaleX:     1.0,
 				ScaleY:     1.0,
-				TranslateX: float64(index * 500000), // offset tables to avoid overlap
+				TranslateX: translateX,
 				TranslateY: float64(index * 100000),
 				Unit:       "EMU",
 			},
@@ -315,11 +357,14 @@ func (d *Deck) createTableStructureRequest(slideObjectID string, table *Table, i
 	requests = append(requests, &slides.Request{
 		CreateTable: createTableReq,
 	})
+	requests = append(requests, tableColumnWidthRequests(tableObjectID, columnWidths)...)
+	requests = append(requests, tableMergeRequests(tableObjectID, table)...)
 
-	// Set description to mark as markdown-generated table
+	// Set description to mark as markdown-generated table, and title to store the style override
 	requests = append(requests, &slides.Request{
 		UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
 			ObjectId:    tableObjectID,
+			Title:       table.Style.altTextTitle(),
 			Description: descriptionTableFromMarkdown,
 		},
 	})
@@ -391,10 +436,17 @@ func (d *Deck) collectTableContentRequests(slideObjectID string, tables []*Table
 // createTableContentRequests creates requests to fill table content.
 func (d *Deck) createTableContentRequests(tableObjectID string, table *Table) ([]*slides.Request, error) {
 	var requests []*slides.Request
+	ts, err := d.tableStyle.withOverride(table.Style)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table style: %w", err)
+	}
 
 	// Fill table cells with content
 	for rowIdx, row := range table.Rows {
 		for colIdx, cell := range row.Cells {
+			if coveredByMerge(table, rowIdx, colIdx) {
+				continue
+			}
 			// Create text from fragments
 			text := ""
 			for _, fragment := range cell.Fragments {
@@ -422,7 +474,7 @@ func (d *Deck) createTableContentRequests(tableObjectID string, table *Table) ([
 
 			// Apply base text style from tableStyle (before fragment styles)
 			textLength := int64(countString(text))
-			if cellStyle := d.tableStyle.cellStyle(rowIdx, colIdx); cellStyle != nil && cellStyle.TextStyle != nil && textLength > 0 {
+			if cellStyle := ts.cellStyle(rowIdx, colIdx); cellStyle != nil && cellStyle.TextStyle != nil && textLength > 0 {
 				req := buildTableCellTextStyleRequest(cellStyle.TextStyle)
 				if req != nil {
 					requests = append(requests, &slides.Request{
@@ -441,6 +493,23 @@ func (d *Deck) createTableContentRequests(tableObjectID string, table *Table) ([
 				}
 			}
 
+			// Reduce font size of wide tables
+			if fontSize := d.tableFontSize(table, rowIdx, colIdx); fontSize > 0 && textLength > 0 {
+				requests = append(requests, &slides.Request{
+					UpdateTextStyle: &slides.UpdateTextStyleRequest{
+						ObjectId:     tableObjectID,
+						CellLocation: cellLocation,
+						Style: &slides.TextStyle{
+							FontSize: &slides.Dimension{Magnitude: fontSize, Unit: "PT"},
+						},
+						TextRange: &slides.Range{
+							Type: "ALL",
+						},
+						Fields: "fontSize",
+					},
+				})
+			}
+
 			// Apply formatting if needed
 			if len(cell.Fragments) > 0 {
 				startIndex := int64(0)
@@ -490,16 +559,25 @@ func (d *Deck) createTableContentRequests(tableObjectID string, table *Table) ([
 	}
 
 	// Apply cell styles from tableStyle
-	requests = append(requests, d.applyTableCellStyles(tableObjectID, table)...)
+	requests = append(requests, applyTableCellStyles(ts, tableObjectID, table)...)
 
 	// Apply border styles from tableStyle
-	requests = append(requests, d.applyTableBorderStyles(tableObjectID, table)...)
+	requests = append(requests, applyTableBorderStyles(ts, tableObjectID, table)...)
 
 	return requests, nil
 }
 
-// applyTableCellStyles applies cell styles from d.tableStyle.
-func (d *Deck) applyTableCellStyles(tableObjectID string, table *Table) []*slides.Request {
+// hasColumnAlignment reports whether the cell is aligned other than the default START, e.g. with `:---:` or `---:` in markdown.
+func hasColumnAlignment(table *Table, rowIdx, colIdx int) bool {
+	if rowIdx >= len(table.Rows) || colIdx >= len(table.Rows[rowIdx].Cells) {
+		return false
+	}
+	cell := table.Rows[rowIdx].Cells[colIdx]
+	return cell != nil && cell.Alignment != "" && cell.Alignment != "START"
+}
+
+// applyTableCellStyles applies cell styles from the table style.
+func applyTableCellStyles(ts *TableStyle, tableObjectID string, table *Table) []*slides.Request {
 	var requests []*slides.Request
 
 	rows := len(table.Rows)
@@ -520,8 +598,8 @@ func (d *Deck) applyTableCellStyles(tableObjectID string, table *Table) []*slide
 
 	for rowIdx := 0; rowIdx < rows; rowIdx++ {
 		for colIdx := 0; colIdx < cols; colIdx++ {
-			cellStyle := d.tableStyle.cellStyle(rowIdx, colIdx)
-			if cellStyle == nil {
+			cellStyle := ts.cellStyle(rowIdx, colIdx)
+			if cellStyle == nil || coveredByMerge(table, rowIdx, colIdx) {
 				continue
 			}
 
@@ -558,8 +636,8 @@ func (d *Deck) applyTableCellStyles(tableObjectID string, table *Table) []*slide
 				})
 			}
 
-			// Apply paragraph style (horizontal alignment)
-			if cellStyle.ParagraphStyle != nil && cellStyle.ParagraphStyle.Alignment != "" {
+			// Apply paragraph style (horizontal alignment) unless the column alignment in markdown overrides it.
+			if cellStyle.ParagraphStyle != nil && cellStyle.ParagraphStyle.Alignment != "" && !hasColumnAlignment(table, rowIdx, colIdx) {
 				requests = append(requests, &slides.Request{
 					UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
 						ObjectId: tableObjectID,
@@ -583,14 +661,14 @@ func (d *Deck) applyTableCellStyles(tableObjectID string, table *Table) []*slide
 	return requests
 }
 
-// applyTableBorderStyles applies border styles from d.tableStyle.BorderStyle.
-func (d *Deck) applyTableBorderStyles(tableObjectID string, table *Table) []*slides.Request {
-	if d.tableStyle == nil || d.tableStyle.BorderStyle == nil {
+// applyTableBorderStyles applies border styles from the border style of the table style.
+func applyTableBorderStyles(ts *TableStyle, tableObjectID string, table *Table) []*slides.Request {
+	if ts == nil || ts.BorderStyle == nil {
 		return nil
 	}
 
 	var requests []*slides.Request
-	bs := d.tableStyle.BorderStyle
+	bs := ts.BorderStyle
 
 	rows := len(table.Rows)
 	if rows == 0 {
@@ -0,0 +1,103 @@
+package deck
+
+import (
+	"fmt"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// validateTableMerges validates that the merges of the table are within the table and do not overlap.
+func validateTableMerges(table *Table) error {
+	rows := len(table.Rows)
+	cols := tableColumns(table)
+	covered := map[[2]int]bool{}
+	for _, m := range table.Merges {
+		if m == nil {
+			continue
+		}
+		if m.Row < 0 || m.Column < 0 || m.RowSpan < 1 || m.ColumnSpan < 1 ||
+			m.Row+m.RowSpan > rows || m.Column+m.ColumnSpan > cols {
+			return fmt.Errorf("table merge out of range (%dx%d table): %+v", rows, cols, *m)
+		}
+		if m.RowSpan == 1 && m.ColumnSpan == 1 {
+			return fmt.Errorf("table merge must span two or more cells: %+v", *m)
+		}
+		for r := m.Row; r < m.Row+m.RowSpan; r++ {
+			for c := m.Column; c < m.Column+m.ColumnSpan; c++ {
+				if covered[[2]int{r, c}] {
+					return fmt.Errorf("table merges overlap at row %d column %d", r, c)
+				}
+				covered[[2]int{r, c}] = true
+			}
+		}
+	}
+	return nil
+}
+
+// coveredByMerge reports whether the cell is merged into another cell, so it has no content of its own.
+func coveredByMerge(table *Table, rowIdx, colIdx int) bool {
+	for _, m := range table.Merges {
+		if m == nil || (rowIdx == m.Row && colIdx == m.Column) {
+			continue
+		}
+		if m.Row <= rowIdx && rowIdx < m.Row+m.RowSpan && m.Column <= colIdx && colIdx < m.Column+m.ColumnSpan {
+			return true
+		}
+	}
+	return false
+}
+
+// tableMergeRequests creates requests to merge the cells of the table.
+func tableMergeRequests(tableObjectID string, table *Table) []*slides.Request {
+	var requests []*slides.Request
+	for _, m := range table.Merges {
+		if m == nil {
+			continue
+		}
+		requests = append(requests, &slides.Request{
+			MergeTableCells: &slides.MergeTableCellsRequest{
+				ObjectId: tableObjectID,
+				TableRange: &slides.TableRange{
+					Location: &slides.TableCellLocation{
+						RowIndex:    int64(m.Row),
+						ColumnIndex: int64(m.Column),
+					},
+					RowSpan:    int64(m.RowSpan),
+					ColumnSpan: int64(m.ColumnSpan),
+				},
+			},
+		})
+	}
+	return requests
+}
+
+// readTableMerges returns the merged cells of the Google Slides table.
+func readTableMerges(slidesTable *slides.Table) []*TableMerge {
+	var merges []*TableMerge
+	for i, row := range slidesTable.TableRows {
+		if row == nil {
+			continue
+		}
+		for j, cell := range row.TableCells {
+			if cell == nil || (cell.RowSpan <= 1 && cell.ColumnSpan <= 1) {
+				continue
+			}
+			r, c := i, j
+			if cell.Location != nil {
+				r, c = int(cell.Location.RowIndex), int(cell.Location.ColumnIndex)
+			}
+			merges = append(merges, &TableMerge{
+				Row:        r,
+				Column:     c,
+				RowSpan:    int(max(cell.RowSpan, 1)),
+				ColumnSpan: int(max(cell.ColumnSpan, 1)),
+			})
+		}
+	}
+	return merges
+}
+
+// hasTableMerges reports whether the Google Slides table has merged cells.
+func hasTableMerges(slidesTable *slides.Table) bool {
+	return len(readTableMerges(slidesTable)) > 0
+}
@@ -0,0 +1,64 @@
+package deck
+
+import (
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestValidateTableMerges(t *testing.T) {
+	t.Parallel()
+	newTable := func(merges ...*TableMerge) *Table {
+		row := func() *TableRow { return &TableRow{Cells: []*TableCell{{}, {}, {}}} }
+		return &Table{Rows: []*TableRow{row(), row(), row()}, Merges: merges}
+	}
+	tests := []struct {
+		name    string
+		table   *Table
+		wantErr bool
+	}{
+		{"no merges", newTable(), false},
+		{"horizontal and vertical", newTable(&TableMerge{0, 0, 1, 3}, &TableMerge{1, 0, 2, 1}), false},
+		{"out of range", newTable(&TableMerge{0, 1, 1, 3}), true},
+		{"single cell", newTable(&TableMerge{0, 0, 1, 1}), true},
+		{"overlap", newTable(&TableMerge{0, 0, 2, 2}, &TableMerge{1, 1, 2, 2}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := validateTableMerges(tt.table); (err != nil) != tt.wantErr {
+				t.Errorf("validateTableMerges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTableMergeRequestsRoundTrip(t *testing.T) {
+	t.Parallel()
+	table := &Table{
+		Rows: []*TableRow{
+			{Cells: []*TableCell{{Fragments: []*Fragment{{Value: "Scores"}}}, {}}},
+			{Cells: []*TableCell{{Fragments: []*Fragment{{Value: "90"}}}, {Fragments: []*Fragment{{Value: "80"}}}}},
+		},
+		Merges: []*TableMerge{{Row: 0, Column: 0, RowSpan: 1, ColumnSpan: 2}},
+	}
+	reqs := tableMergeRequests("table", table)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	r := reqs[0].MergeTableCells.TableRange
+	if r.Location.RowIndex != 0 || r.Location.ColumnIndex != 0 || r.RowSpan != 1 || r.ColumnSpan != 2 {
+		t.Errorf("got table range %+v, want 1x2 at 0,0", r)
+	}
+	if !coveredByMerge(table, 0, 1) || coveredByMerge(table, 0, 0) || coveredByMerge(table, 1, 1) {
+		t.Error("only the cell merged into another should be covered")
+	}
+
+	slidesTable := &slides.Table{TableRows: []*slides.TableRow{
+		{TableCells: []*slides.TableCell{{RowSpan: 1, ColumnSpan: 2}, {}}},
+		{TableCells: []*slides.TableCell{{RowSpan: 1, ColumnSpan: 1}, {RowSpan: 1, ColumnSpan: 1}}},
+	}}
+	if got := readTableMerges(slidesTable); len(got) != 1 || *got[0] != *table.Merges[0] {
+		t.Errorf("got merges %v, want %v", got, table.Merges)
+	}
+}
@@ -1,6 +1,9 @@
 package deck
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"google.golang.org/api/slides/v1"
@@ -44,6 +47,133 @@ type TableBorderStyle struct {
 	DataOtherColBottom *slides.TableBorderProperties // [1,1] Bottom -> data rows, col 1+ bottom (except outer)
 }
 
+// TableStyleOverride overrides the table style for a specific table.
+// Empty fields fall back to the table style detected from the style layout.
+type TableStyleOverride struct {
+	HeaderBackground string  `json:"header_background,omitempty"` // Background color of the header row, e.g. "#336699"
+	TextColor        string  `json:"text_color,omitempty"`        // Text color of all cells, e.g. "#333333"
+	BorderWidth      float64 `json:"border_width,omitempty"`      // Width of all borders in points
+}
+
+// withOverride returns a copy of the table style with the override merged over it.
+func (ts *TableStyle) withOverride(o *TableStyleOverride) (*TableStyle, error) {
+	if ts == nil {
+		ts = defaultTableStyle()
+	}
+	if o == nil {
+		return ts, nil
+	}
+	merged := &TableStyle{
+		HeaderFirstCol:  ts.HeaderFirstCol.clone(),
+		HeaderOtherCols: ts.HeaderOtherCols.clone(),
+		DataFirstCol:    ts.DataFirstCol.clone(),
+		DataOtherCols:   ts.DataOtherCols.clone(),
+		BorderStyle:     ts.BorderStyle,
+	}
+	if o.HeaderBackground != "" {
+		c, err := parseHexColor(o.HeaderBackground)
+		if err != nil {
+			return nil, fmt.Errorf("invalid header background: %w", err)
+		}
+		fill := &slides.TableCellBackgroundFill{
+			SolidFill: &slides.SolidFill{Color: &slides.OpaqueColor{RgbColor: c}},
+		}
+		merged.HeaderFirstCol.BackgroundFill = fill
+		merged.HeaderOtherCols.BackgroundFill = fill
+	}
+	if o.TextColor != "" {
+		c, err := parseHexColor(o.TextColor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid text color: %w", err)
+		}
+		for _, s := range []*TableCellStyle{merged.HeaderFirstCol, merged.HeaderOtherCols, merged.DataFirstCol, merged.DataOtherCols} {
+			if s.TextStyle == nil {
+				s.TextStyle = &slides.TextStyle{}
+			}
+			s.TextStyle.ForegroundColor = &slides.OptionalColor{OpaqueColor: &slides.OpaqueColor{RgbColor: c}}
+		}
+	}
+	if o.BorderWidth < 0 {
+		return nil, fmt.Errorf("invalid border width: %v", o.BorderWidth)
+	}
+	if o.BorderWidth > 0 {
+		bs := &TableBorderStyle{}
+		if ts.BorderStyle != nil {
+			*bs = *ts.BorderStyle
+		}
+		weight := &slides.Dimension{Magnitude: o.BorderWidth, Unit: "PT"}
+		for _, p := range []**slides.TableBorderProperties{
+			&bs.OuterHorizontal, &bs.OuterVertical,
+			&bs.HeaderFirstColRight, &bs.HeaderFirstColBottom, &bs.HeaderOtherColRight, &bs.HeaderOtherColBottom,
+			&bs.DataFirstColRight, &bs.DataFirstColBottom, &bs.DataOtherColRight, &bs.DataOtherColBottom,
+		} {
+			props := &slides.TableBorderProperties{}
+			if *p != nil {
+				*props = **p
+			}
+			props.Weight = weight
+			*p = props
+		}
+		merged.BorderStyle = bs
+	}
+	return merged, nil
+}
+
+// clone returns a copy of the cell style that can be modified without affecting the original.
+func (s *TableCellStyle) clone() *TableCellStyle {
+	c := &TableCellStyle{}
+	if s != nil {
+		*c = *s
+	}
+	if c.TextStyle != nil {
+		ts := *c.TextStyle
+		c.TextStyle = &ts
+	}
+	return c
+}
+
+// altTextTitle returns the override encoded to be stored as the alt text title of the table,
+// so that it can be read back from the presentation with readTableStyleOverride.
+func (o *TableStyleOverride) altTextTitle() string {
+	if o == nil {
+		return ""
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// readTableStyleOverride reads back the table style override stored in the alt text title of the table element.
+func readTableStyleOverride(element *slides.PageElement) *TableStyleOverride {
+	if element.Description != descriptionTableFromMarkdown || element.Title == "" {
+		return nil
+	}
+	o := &TableStyleOverride{}
+	if err := json.Unmarshal([]byte(element.Title), o); err != nil {
+		return nil
+	}
+	return o
+}
+
+// parseHexColor parses a color in the form of "#RRGGBB".
+func parseHexColor(s string) (*slides.RgbColor, error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) != 6 {
+		return nil, fmt.Errorf("color must be in the form of #RRGGBB: %q", s)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("color must be in the form of #RRGGBB: %q", s)
+	}
+	return &slides.RgbColor{
+		Red:   float64(v>>16&0xff) / 255,
+		Green: float64(v>>8&0xff) / 255,
+		Blue:  float64(v&0xff) / 255,
+	}, nil
+}
+
 // cellStyle returns the appropriate cell style based on row and column index.
 func (ts *TableStyle) cellStyle(rowIdx, colIdx int) *TableCellStyle {
 	if ts == nil {
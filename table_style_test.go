@@ -1,6 +1,7 @@
 package deck
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -714,3 +715,164 @@ func TestBuildBorderFields(t *testing.T) {
 		}
 	})
 }
+
+func TestTableStyleWithOverride(t *testing.T) {
+	t.Parallel()
+	base := defaultTableStyle()
+
+	got, err := base.withOverride(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != base {
+		t.Error("withOverride(nil) should return the table style as is")
+	}
+
+	got, err = base.withOverride(&TableStyleOverride{HeaderBackground: "#336699", TextColor: "#ffffff", BorderWidth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantBg := &slides.RgbColor{Red: 0x33 / 255.0, Green: 0x66 / 255.0, Blue: 0x99 / 255.0}
+	for _, s := range []*TableCellStyle{got.HeaderFirstCol, got.HeaderOtherCols} {
+		if diff := cmp.Diff(wantBg, s.BackgroundFill.SolidFill.Color.RgbColor); diff != "" {
+			t.Errorf("header background mismatch (-want +got):\n%s", diff)
+		}
+		if !s.TextStyle.Bold {
+			t.Error("header text style should be kept")
+		}
+	}
+	if got.DataFirstCol.BackgroundFill != nil {
+		t.Error("data background should not be overridden")
+	}
+	for _, s := range []*TableCellStyle{got.HeaderFirstCol, got.HeaderOtherCols, got.DataFirstCol, got.DataOtherCols} {
+		if c := s.TextStyle.ForegroundColor.OpaqueColor.RgbColor; c.Red != 1 || c.Green != 1 || c.Blue != 1 {
+			t.Errorf("got text color %+v, want white", c)
+		}
+	}
+	if w := got.BorderStyle.DataOtherColBottom.Weight; w.Magnitude != 2 || w.Unit != "PT" {
+		t.Errorf("got border weight %+v, want 2PT", w)
+	}
+
+	// The base style is not modified.
+	if base.HeaderFirstCol.BackgroundFill.SolidFill.Color.RgbColor.Red != 0.95 || base.HeaderFirstCol.TextStyle.ForegroundColor != nil || base.BorderStyle != nil {
+		t.Error("base table style should not be modified")
+	}
+
+	if _, err := base.withOverride(&TableStyleOverride{TextColor: "red"}); err == nil {
+		t.Error("expected error for invalid color but got none")
+	}
+}
+
+func TestApplyTableCellStylesKeepsColumnAlignment(t *testing.T) {
+	t.Parallel()
+	start := &TableCellStyle{ParagraphStyle: &slides.ParagraphStyle{Alignment: "START"}}
+	ts := &TableStyle{HeaderFirstCol: start, HeaderOtherCols: start, DataFirstCol: start, DataOtherCols: start}
+	row := func(isHeader bool) *TableRow {
+		return &TableRow{Cells: []*TableCell{
+			{Alignment: "START", IsHeader: isHeader},
+			{Alignment: "CENTER", IsHeader: isHeader},
+			{Alignment: "END", IsHeader: isHeader},
+		}}
+	}
+	table := &Table{Rows: []*TableRow{row(true), row(false)}}
+
+	var got []string
+	for _, r := range applyTableCellStyles(ts, "table", table) {
+		if p := r.UpdateParagraphStyle; p != nil {
+			got = append(got, fmt.Sprintf("%d:%d:%s", p.CellLocation.RowIndex, p.CellLocation.ColumnIndex, p.Style.Alignment))
+		}
+	}
+	// Only the cells without column alignment get the alignment of the table style.
+	if diff := cmp.Diff([]string{"0:0:START", "1:0:START"}, got); diff != "" {
+		t.Errorf("paragraph style requests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTablesEqualStyle(t *testing.T) {
+	newTable := func(style *TableStyleOverride) []*Table {
+		return []*Table{{
+			Rows:  []*TableRow{{Cells: []*TableCell{{Fragments: []*Fragment{{Value: "a"}}}}}},
+			Style: style,
+		}}
+	}
+	tests := []struct {
+		name string
+		a, b *TableStyleOverride
+		want bool
+	}{
+		{"no style", nil, nil, true},
+		{"same style", &TableStyleOverride{TextColor: "#333333"}, &TableStyleOverride{TextColor: "#333333"}, true},
+		{"style added", nil, &TableStyleOverride{TextColor: "#333333"}, false},
+		{"style changed", &TableStyleOverride{BorderWidth: 1}, &TableStyleOverride{BorderWidth: 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tablesEqual(newTable(tt.a), newTable(tt.b)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTableStyleOverrideIsIdempotent(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	// Reflect the table requests on the presentation, as the Slides API does.
+	s.batchUpdateHook = func(req *slides.BatchUpdatePresentationRequest) error {
+		page := s.presentation.Slides[0]
+		for _, r := range req.Requests {
+			switch {
+			case r.CreateTable != nil:
+				table := &slides.Table{}
+				for range r.CreateTable.Rows {
+					row := &slides.TableRow{}
+					for range r.CreateTable.Columns {
+						row.TableCells = append(row.TableCells, &slides.TableCell{})
+					}
+					table.TableRows = append(table.TableRows, row)
+				}
+				page.PageElements = append(page.PageElements, &slides.PageElement{
+					ObjectId: r.CreateTable.ObjectId,
+					Table:    table,
+				})
+			case r.UpdatePageElementAltText != nil:
+				if e := pageElementByID(page, r.UpdatePageElementAltText.ObjectId); e != nil {
+					e.Title = r.UpdatePageElementAltText.Title
+					e.Description = r.UpdatePageElementAltText.Description
+				}
+			case r.InsertText != nil && r.InsertText.CellLocation != nil:
+				if e := pageElementByID(page, r.InsertText.ObjectId); e != nil {
+					loc := r.InsertText.CellLocation
+					e.Table.TableRows[loc.RowIndex].TableCells[loc.ColumnIndex].Text = &slides.TextContent{
+						TextElements: []*slides.TextElement{{TextRun: &slides.TextRun{Content: r.InsertText.Text + "\n"}}},
+					}
+				}
+			}
+		}
+		return nil
+	}
+	d := newFakeDeck(t, s)
+	ss := Slides{{
+		Layout: "Title and Content",
+		Titles: []string{"a"},
+		Tables: []*Table{{
+			Rows:  []*TableRow{{Cells: []*TableCell{{Fragments: []*Fragment{{Value: "h"}}, IsHeader: true}}}},
+			Style: &TableStyleOverride{TextColor: "#333333"},
+		}},
+	}}
+
+	if _, err := d.Apply(t.Context(), ss); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.presentation.Slides[0].PageElements[1].Title; got != `{"text_color":"#333333"}` {
+		t.Errorf("got alt text title %q, want the style override", got)
+	}
+
+	s.batchUpdates = nil
+	result, err := d.Apply(t.Context(), ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 0 || len(s.batchUpdates) != 0 {
+		t.Errorf("got %+v and %d batch updates on the second apply, want none", result, len(s.batchUpdates))
+	}
+}
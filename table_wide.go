@@ -0,0 +1,239 @@
+package deck
+
+import (
+	"fmt"
+	"slices"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// WideTablePolicy is the policy for tables with more columns than the threshold.
+type WideTablePolicy string
+
+const (
+	// WideTablePolicyShrink reduces the font size of wide tables.
+	WideTablePolicyShrink WideTablePolicy = "shrink"
+	// WideTablePolicySplit splits the columns of wide tables across continuation slides.
+	// The header row is preserved on every continuation slide.
+	WideTablePolicySplit WideTablePolicy = "split"
+)
+
+const (
+	tableMargin           = 365760  // 0.4 inch on both sides of the content area
+	minTableColumnWidth   = 406400  // Minimum column width allowed by the Slides API (32pt)
+	defaultTableColumnEMU = 1000000 // Default width per column
+	defaultTableFontSize  = 14.0    // Default font size of table cells in Google Slides
+	minTableFontSize      = 6.0
+)
+
+// tableColumns returns the number of columns of the table.
+func tableColumns(table *Table) int {
+	cols := 0
+	for _, row := range table.Rows {
+		cols = max(cols, len(row.Cells))
+	}
+	return cols
+}
+
+// fitTableColumnWidths returns the widths of the columns so that the table fits the content width.
+// The widths are proportional to the longest text in each column, but are at least minTableColumnWidth.
+// If even the minimum widths do not fit, the columns share the content width equally, which is narrower
+// than the Slides API allows, so such tables should be split with WideTablePolicySplit.
+// It returns nil if the table fits with the default column width.
+func fitTableColumnWidths(table *Table, contentWidth float64) []float64 {
+	cols := tableColumns(table)
+	if cols == 0 || float64(cols*defaultTableColumnEMU) <= contentWidth {
+		return nil
+	}
+	if float64(cols*minTableColumnWidth) > contentWidth {
+		widths := make([]float64, cols)
+		for i := range widths {
+			widths[i] = contentWidth / float64(cols)
+		}
+		return widths
+	}
+	weights := make([]float64, cols)
+	total := 0.0
+	for i := range cols {
+		longest := 1
+		for _, row := range table.Rows {
+			if i >= len(row.Cells) || row.Cells[i] == nil {
+				continue
+			}
+			n := 0
+			for _, f := range row.Cells[i].Fragments {
+				n += countString(f.Value)
+			}
+			longest = max(longest, n)
+		}
+		weights[i] = float64(longest)
+		total += weights[i]
+	}
+	// Distribute the width remaining after the minimum widths in proportion to the weights.
+	remaining := contentWidth - float64(cols*minTableColumnWidth)
+	widths := make([]float64, cols)
+	for i, w := range weights {
+		widths[i] = minTableColumnWidth + remaining*w/total
+	}
+	return widths
+}
+
+// tableColumnWidthRequests returns the requests to set the widths of the columns of the table.
+func tableColumnWidthRequests(tableObjectID string, widths []float64) []*slides.Request {
+	reqs := make([]*slides.Request, 0, len(widths))
+	for i, w := range widths {
+		reqs = append(reqs, &slides.Request{
+			UpdateTableColumnProperties: &slides.UpdateTableColumnPropertiesRequest{
+				ObjectId:      tableObjectID,
+				ColumnIndices: []int64{int64(i)},
+				TableColumnProperties: &slides.TableColumnProperties{
+					ColumnWidth: &slides.Dimension{Magnitude: w, Unit: "EMU"},
+				},
+				Fields: "columnWidth",
+			},
+		})
+	}
+	return reqs
+}
+
+// tableFontSize returns the font size of the table cells reduced in proportion to the columns exceeding maxColumns.
+// It returns 0 if the font size should not be changed.
+func (d *Deck) tableFontSize(table *Table, rowIdx, colIdx int) float64 {
+	if d.wideTablePolicy != WideTablePolicyShrink || d.wideTableMaxColumns < 1 {
+		return 0
+	}
+	cols := tableColumns(table)
+	if cols <= d.wideTableMaxColumns {
+		return 0
+	}
+	base := defaultTableFontSize
+	if s := d.tableStyle.cellStyle(rowIdx, colIdx); s != nil && s.TextStyle != nil && s.TextStyle.FontSize != nil {
+		base = s.TextStyle.FontSize.Magnitude
+	}
+	return max(minTableFontSize, base*float64(d.wideTableMaxColumns)/float64(cols))
+}
+
+// splitWideTables splits slides with tables having more columns than maxColumns into continuation slides,
+// and returns the slides and the pages mapped to them.
+// Each continuation slide has the titles and subtitles of the original slide and the next maxColumns columns
+// of the wide tables, including their header row.
+func splitWideTables(ss Slides, pages []int, maxColumns int) (Slides, []int) {
+	if maxColumns < 1 {
+		return ss, pages
+	}
+	var (
+		result    Slides
+		pageRange = make(map[int][]int, len(ss)) // key: original page
+	)
+	for i, slide := range ss {
+		n := 1
+		ranges := make([][][2]int, len(slide.Tables))
+		for j, table := range slide.Tables {
+			ranges[j] = tableColumnRanges(table, maxColumns)
+			n = max(n, len(ranges[j]))
+		}
+		for k := range n {
+			pageRange[i+1] = append(pageRange[i+1], len(result)+1)
+			if n == 1 {
+				result = append(result, slide)
+				continue
+			}
+			s := &Slide{
+				Layout:         slide.Layout,
+				Freeze:         slide.Freeze,
+				Skip:           slide.Skip,
+				Titles:         slide.Titles,
+				TitleBodies:    slide.TitleBodies,
+				Subtitles:      slide.Subtitles,
+				SubtitleBodies: slide.SubtitleBodies,
+			}
+			if k == 0 {
+				s.Bodies = slide.Bodies
+				s.Images = slide.Images
+				s.BlockQuotes = slide.BlockQuotes
+				s.SpeakerNote = slide.SpeakerNote
+			}
+			for j, table := range slide.Tables {
+				if len(ranges[j]) <= 1 {
+					if k == 0 {
+						s.Tables = append(s.Tables, table)
+					}
+					continue
+				}
+				if k >= len(ranges[j]) {
+					continue
+				}
+				if chunk := tableColumnsChunk(table, ranges[j][k][0], ranges[j][k][1]); chunk != nil {
+					s.Tables = append(s.Tables, chunk)
+				}
+			}
+			result = append(result, s)
+		}
+	}
+	var mapped []int
+	for _, page := range pages {
+		mapped = append(mapped, pageRange[page]...)
+	}
+	return result, mapped
+}
+
+// tableColumnRanges returns the ranges [from, to) of the columns to split the table into.
+// Each range has at most maxColumns columns, except that a range never cuts through merged cells:
+// it ends before the merged cells, or is widened to include them if they start at the beginning of the range.
+func tableColumnRanges(table *Table, maxColumns int) [][2]int {
+	crosses := func(to int) bool {
+		return slices.ContainsFunc(table.Merges, func(m *TableMerge) bool {
+			return m != nil && m.Column < to && to < m.Column+m.ColumnSpan
+		})
+	}
+	cols := tableColumns(table)
+	var ranges [][2]int
+	for from := 0; from < cols; {
+		to := min(from+maxColumns, cols)
+		for to > from && crosses(to) {
+			to--
+		}
+		if to == from {
+			to = min(from+maxColumns, cols)
+			for crosses(to) {
+				to++
+			}
+		}
+		ranges = append(ranges, [2]int{from, to})
+		from = to
+	}
+	return ranges
+}
+
+// tableColumnsChunk returns the table with the columns in [from, to), or nil if there are no such columns.
+func tableColumnsChunk(table *Table, from, to int) *Table {
+	chunk := &Table{}
+	hasColumns := false
+	for _, row := range table.Rows {
+		r := &TableRow{}
+		if from < len(row.Cells) {
+			r.Cells = row.Cells[from:min(to, len(row.Cells))]
+			hasColumns = true
+		}
+		chunk.Rows = append(chunk.Rows, r)
+	}
+	if !hasColumns {
+		return nil
+	}
+	// The columns are split between merged cells, so the merges are all either within the columns or not.
+	for _, m := range table.Merges {
+		if m != nil && from <= m.Column && m.Column+m.ColumnSpan <= to {
+			chunk.Merges = append(chunk.Merges, &TableMerge{Row: m.Row, Column: m.Column - from, RowSpan: m.RowSpan, ColumnSpan: m.ColumnSpan})
+		}
+	}
+	return chunk
+}
+
+func validateWideTablePolicy(policy WideTablePolicy) error {
+	switch policy {
+	case WideTablePolicyShrink, WideTablePolicySplit:
+		return nil
+	default:
+		return fmt.Errorf("invalid wide table policy: %q", policy)
+	}
+}
@@ -0,0 +1,186 @@
+package deck
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func newWideTable(cols, rows int) *Table {
+	table := &Table{}
+	for r := range rows {
+		row := &TableRow{}
+		for c := range cols {
+			row.Cells = append(row.Cells, &TableCell{
+				Fragments: []*Fragment{{Value: fmt.Sprintf("r%dc%d", r, c)}},
+				IsHeader:  r == 0,
+			})
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func TestSplitWideTables(t *testing.T) {
+	wide := newWideTable(10, 3)
+	ss := Slides{
+		{Titles: []string{"first"}},
+		{Titles: []string{"wide"}, Bodies: []*Body{{}}, Tables: []*Table{wide}, SpeakerNote: "note"},
+		{Titles: []string{"last"}},
+	}
+	got, pages := splitWideTables(ss, []int{2, 3}, 4)
+	if len(got) != 5 {
+		t.Fatalf("got %d slides, want 5", len(got))
+	}
+	if want := []int{2, 3, 4, 5}; !slices.Equal(pages, want) {
+		t.Errorf("got pages %v, want %v", pages, want)
+	}
+	for i, wantCols := range []int{4, 4, 2} {
+		s := got[i+1]
+		if !slices.Equal(s.Titles, []string{"wide"}) {
+			t.Errorf("slide %d: got titles %v", i, s.Titles)
+		}
+		if len(s.Tables) != 1 {
+			t.Fatalf("slide %d: got %d tables, want 1", i, len(s.Tables))
+		}
+		table := s.Tables[0]
+		if tableColumns(table) != wantCols || len(table.Rows) != 3 {
+			t.Errorf("slide %d: got %dx%d table, want %dx3", i, tableColumns(table), len(table.Rows), wantCols)
+		}
+		if want := fmt.Sprintf("r0c%d", i*4); table.Rows[0].Cells[0].Fragments[0].Value != want || !table.Rows[0].Cells[0].IsHeader {
+			t.Errorf("slide %d: header row should be preserved, got %q", i, table.Rows[0].Cells[0].Fragments[0].Value)
+		}
+		if (i == 0) != (s.SpeakerNote == "note") || (i == 0) != (len(s.Bodies) == 1) {
+			t.Errorf("slide %d: bodies and speaker note should be only on the first slide", i)
+		}
+	}
+}
+
+func TestWideTableShrink(t *testing.T) {
+	d := &Deck{tableStyle: defaultTableStyle()}
+	if err := WithWideTablePolicy(WideTablePolicyShrink, 5)(d); err != nil {
+		t.Fatal(err)
+	}
+	reqs, err := d.createTableContentRequests("table", newWideTable(10, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sizes int
+	for _, r := range reqs {
+		if r.UpdateTextStyle != nil && r.UpdateTextStyle.Fields == "fontSize" {
+			sizes++
+			if got := r.UpdateTextStyle.Style.FontSize.Magnitude; got != 7 {
+				t.Errorf("got font size %v, want 7", got)
+			}
+		}
+	}
+	if sizes != 20 {
+		t.Errorf("got %d font size requests, want 20", sizes)
+	}
+
+	if err := WithWideTablePolicy(WideTablePolicyShrink, 10)(d); err != nil {
+		t.Fatal(err)
+	}
+	reqs, err = d.createTableContentRequests("table", newWideTable(10, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range reqs {
+		if r.UpdateTextStyle != nil && r.UpdateTextStyle.Fields == "fontSize" {
+			t.Fatal("table within max columns should not be shrunk")
+		}
+	}
+}
+
+func TestCreateTableStructureRequestFitsContentArea(t *testing.T) {
+	const pageWidth = 9144000
+	d := &Deck{presentation: &slides.Presentation{
+		PageSize: &slides.Size{Width: &slides.Dimension{Magnitude: pageWidth, Unit: "EMU"}},
+	}}
+	reqs, err := d.createTableStructureRequest("slide", newWideTable(10, 2), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := reqs[0].CreateTable.ElementProperties
+	if got, want := props.Size.Width.Magnitude, float64(pageWidth-2*tableMargin); math.Abs(got-want) > 1 {
+		t.Errorf("got width %v, want %v", got, want)
+	}
+	if props.Transform.TranslateX+props.Size.Width.Magnitude > pageWidth {
+		t.Error("table overflows the slide")
+	}
+	var total float64
+	var columns int
+	for _, r := range reqs {
+		if r.UpdateTableColumnProperties != nil {
+			columns++
+			total += r.UpdateTableColumnProperties.TableColumnProperties.ColumnWidth.Magnitude
+		}
+	}
+	if columns != 10 || math.Abs(total-props.Size.Width.Magnitude) > 1 {
+		t.Errorf("got %d column widths totaling %v", columns, total)
+	}
+
+	// Tables that fit are not changed
+	reqs, err = d.createTableStructureRequest("slide", newWideTable(3, 2), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reqs[0].CreateTable.ElementProperties.Size.Width.Magnitude; got != 3000000 {
+		t.Errorf("got width %v, want 3000000", got)
+	}
+}
+
+func TestSplitWideTablesKeepsMergedCells(t *testing.T) {
+	tests := []struct {
+		name       string
+		merge      *TableMerge
+		wantRanges [][2]int
+	}{
+		{"merge across the boundary", &TableMerge{Row: 1, Column: 3, RowSpan: 2, ColumnSpan: 2}, [][2]int{{0, 3}, {3, 6}}},
+		{"merge wider than max columns", &TableMerge{Row: 1, Column: 0, RowSpan: 1, ColumnSpan: 5}, [][2]int{{0, 5}, {5, 6}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wide := newWideTable(6, 3)
+			wide.Merges = []*TableMerge{tt.merge}
+			got, _ := splitWideTables(Slides{{Tables: []*Table{wide}}}, []int{1}, 4)
+			if len(got) != len(tt.wantRanges) {
+				t.Fatalf("got %d slides, want %d", len(got), len(tt.wantRanges))
+			}
+			var merges int
+			for i, r := range tt.wantRanges {
+				table := got[i].Tables[0]
+				if cols := tableColumns(table); cols != r[1]-r[0] {
+					t.Errorf("slide %d: got %d columns, want %d", i, cols, r[1]-r[0])
+				}
+				for _, m := range table.Merges {
+					merges++
+					if want := (TableMerge{Row: tt.merge.Row, Column: tt.merge.Column - r[0], RowSpan: tt.merge.RowSpan, ColumnSpan: tt.merge.ColumnSpan}); *m != want {
+						t.Errorf("slide %d: got merge %+v, want %+v", i, *m, want)
+					}
+				}
+			}
+			if merges != 1 {
+				t.Errorf("got %d merges, want the merge kept on one slide", merges)
+			}
+		})
+	}
+}
+
+func TestFitTableColumnWidthsClampsToContentArea(t *testing.T) {
+	const contentWidth = 4 * minTableColumnWidth
+	widths := fitTableColumnWidths(newWideTable(5, 2), contentWidth)
+	if len(widths) != 5 {
+		t.Fatalf("got %d widths, want 5", len(widths))
+	}
+	var total float64
+	for _, w := range widths {
+		total += w
+	}
+	if math.Abs(total-contentWidth) > 1 {
+		t.Errorf("got total width %v, want %v", total, float64(contentWidth))
+	}
+}
@@ -0,0 +1,127 @@
+package deck
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"google.golang.org/api/slides/v1"
+)
+
+// minimizeTextRequests replaces a pair of requests deleting all text of a shape and inserting the new text
+// with requests deleting and inserting only the changed part of the text.
+// This keeps unchanged text runs intact. currentTexts is the current text of shapes keyed by object ID.
+// Shapes with bullets or auto text are always rewritten, because their text does not match the inserted text.
+func minimizeTextRequests(requests []*slides.Request, currentTexts map[string]*slides.TextContent) []*slides.Request {
+	deletes := map[string]int{} // key: objectID, value: index of the request deleting all text
+	inserts := map[string]int{} // key: objectID, value: index of the request inserting text
+	skip := map[string]bool{}
+	for i, r := range requests {
+		switch {
+		case r.DeleteText != nil && r.DeleteText.TextRange != nil && r.DeleteText.TextRange.Type == "ALL":
+			if _, ok := deletes[r.DeleteText.ObjectId]; ok {
+				skip[r.DeleteText.ObjectId] = true
+			}
+			deletes[r.DeleteText.ObjectId] = i
+		case r.InsertText != nil:
+			if _, ok := inserts[r.InsertText.ObjectId]; ok || r.InsertText.InsertionIndex != 0 {
+				skip[r.InsertText.ObjectId] = true
+			}
+			inserts[r.InsertText.ObjectId] = i
+		case r.CreateParagraphBullets != nil:
+			skip[r.CreateParagraphBullets.ObjectId] = true
+		}
+	}
+
+	replaced := map[int][]*slides.Request{}
+	for objectID, di := range deletes {
+		ii, ok := inserts[objectID]
+		if !ok || ii < di || skip[objectID] {
+			continue
+		}
+		current, ok := plainText(currentTexts[objectID])
+		if !ok {
+			continue
+		}
+		replaced[di], replaced[ii] = textDiffRequests(objectID, current, requests[ii].InsertText.Text)
+	}
+	if len(replaced) == 0 {
+		return requests
+	}
+
+	var minimized []*slides.Request
+	for i, r := range requests {
+		if reqs, ok := replaced[i]; ok {
+			minimized = append(minimized, reqs...)
+			continue
+		}
+		minimized = append(minimized, r)
+	}
+	return minimized
+}
+
+// plainText returns the text of the shape without the trailing newline that cannot be deleted.
+// It returns false if the text has bullets or auto text.
+func plainText(text *slides.TextContent) (string, bool) {
+	if text == nil {
+		return "", true
+	}
+	var b strings.Builder
+	for _, e := range text.TextElements {
+		switch {
+		case e.AutoText != nil:
+			return "", false
+		case e.ParagraphMarker != nil && e.ParagraphMarker.Bullet != nil:
+			return "", false
+		case e.TextRun != nil:
+			b.WriteString(e.TextRun.Content)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n"), true
+}
+
+// textDiffRequests returns a request deleting the changed part of current and
+// a request inserting the changed part of target. Indices are in UTF-16 code units.
+// The requests are nil if there is nothing to delete or insert.
+func textDiffRequests(objectID, current, target string) (deleteReqs, insertReqs []*slides.Request) {
+	c := utf16.Encode([]rune(current))
+	t := utf16.Encode([]rune(target))
+
+	prefix := 0
+	for prefix < len(c) && prefix < len(t) && c[prefix] == t[prefix] {
+		prefix++
+	}
+	// Do not split a surrogate pair.
+	if prefix > 0 && utf16.IsSurrogate(rune(c[prefix-1])) && c[prefix-1] < 0xdc00 {
+		prefix--
+	}
+	suffix := 0
+	for suffix < len(c)-prefix && suffix < len(t)-prefix && c[len(c)-1-suffix] == t[len(t)-1-suffix] {
+		suffix++
+	}
+	if suffix > 0 && utf16.IsSurrogate(rune(c[len(c)-suffix])) && c[len(c)-suffix] >= 0xdc00 {
+		suffix--
+	}
+
+	if end := len(c) - suffix; prefix < end {
+		deleteReqs = append(deleteReqs, &slides.Request{
+			DeleteText: &slides.DeleteTextRequest{
+				ObjectId: objectID,
+				TextRange: &slides.Range{
+					Type:       "FIXED_RANGE",
+					StartIndex: ptrInt64(int64(prefix)),
+					EndIndex:   ptrInt64(int64(end)),
+				},
+			},
+		})
+	}
+	if end := len(t) - suffix; prefix < end {
+		insertReqs = append(insertReqs, &slides.Request{
+			InsertText: &slides.InsertTextRequest{
+				ObjectId:       objectID,
+				InsertionIndex: int64(prefix),
+				Text:           string(utf16.Decode(t[prefix:end])),
+			},
+		})
+	}
+	return deleteReqs, insertReqs
+}
@@ -0,0 +1,94 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/slides/v1"
+)
+
+func TestTextDiffRequests(t *testing.T) {
+	tests := []struct {
+		current    string
+		target     string
+		wantDelete []int64 // start and end index
+		wantInsert string
+		wantIndex  int64
+	}{
+		{"hello world", "hello there", []int64{6, 11}, "there", 6},
+		{"hello world", "hello world", nil, "", 0},
+		{"hello", "hello world", nil, " world", 5},
+		{"hello world", "world", []int64{0, 6}, "", 0},
+		{"a😀b", "a😁b", []int64{1, 3}, "😁", 1},
+		{"", "new", nil, "new", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.current+"->"+tt.target, func(t *testing.T) {
+			deleteReqs, insertReqs := textDiffRequests("obj", tt.current, tt.target)
+			if tt.wantDelete == nil {
+				if len(deleteReqs) != 0 {
+					t.Errorf("got %d delete requests, want 0", len(deleteReqs))
+				}
+			} else {
+				if len(deleteReqs) != 1 {
+					t.Fatalf("got %d delete requests, want 1", len(deleteReqs))
+				}
+				r := deleteReqs[0].DeleteText.TextRange
+				if diff := cmp.Diff(tt.wantDelete, []int64{*r.StartIndex, *r.EndIndex}); diff != "" {
+					t.Errorf("delete range mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if tt.wantInsert == "" {
+				if len(insertReqs) != 0 {
+					t.Errorf("got %d insert requests, want 0", len(insertReqs))
+				}
+				return
+			}
+			if len(insertReqs) != 1 {
+				t.Fatalf("got %d insert requests, want 1", len(insertReqs))
+			}
+			if got := insertReqs[0].InsertText; got.Text != tt.wantInsert || got.InsertionIndex != tt.wantIndex {
+				t.Errorf("got insert %q at %d, want %q at %d", got.Text, got.InsertionIndex, tt.wantInsert, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestPrepareToApplyPageMinimalTextDiff(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("hello world")}
+	d := newFakeDeck(t, s)
+	if err := d.refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	slide := &Slide{
+		Layout:      "Title and Content",
+		Titles:      []string{"hello there"},
+		TitleBodies: []*Body{{Paragraphs: []*Paragraph{{Fragments: []*Fragment{{Value: "hello there"}}}}}},
+	}
+	reqs, err := d.prepareToApplyPage(t.Context(), 0, slide, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		deletes []*slides.DeleteTextRequest
+		inserts []*slides.InsertTextRequest
+	)
+	for _, r := range reqs {
+		if r.DeleteText != nil && r.DeleteText.ObjectId == "slide-0-title" {
+			deletes = append(deletes, r.DeleteText)
+		}
+		if r.InsertText != nil && r.InsertText.ObjectId == "slide-0-title" {
+			inserts = append(inserts, r.InsertText)
+		}
+	}
+	if len(deletes) != 1 || len(inserts) != 1 {
+		t.Fatalf("got %d deletes and %d inserts, want 1 and 1", len(deletes), len(inserts))
+	}
+	if got := deletes[0].TextRange; got.Type != "FIXED_RANGE" || *got.StartIndex != 6 || *got.EndIndex != 11 {
+		t.Errorf("got delete range %s [%d, %d), want FIXED_RANGE [6, 11)", got.Type, *got.StartIndex, *got.EndIndex)
+	}
+	if got := inserts[0]; got.Text != "there" || got.InsertionIndex != 6 {
+		t.Errorf("got insert %q at %d, want %q at 6", got.Text, got.InsertionIndex, "there")
+	}
+}
@@ -0,0 +1,30 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// callWithTimeout calls fn with a context derived from ctx that is canceled after timeout.
+// If timeout is not positive, fn is called with ctx as is.
+// If the call times out, the returned error identifies the operation.
+func callWithTimeout(ctx context.Context, timeout time.Duration, op string, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := fn(tctx); err != nil {
+		if ctx.Err() == nil && tctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s timed out after %s: %w", op, timeout, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// call calls fn with the request timeout of the Deck.
+func (d *Deck) call(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	return callWithTimeout(ctx, d.requestTimeout, op, fn)
+}
@@ -0,0 +1,74 @@
+package deck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	s := &fakeServer{
+		presentation: newFakePresentation("a"),
+		driveHandler: func(w http.ResponseWriter, r *http.Request) {
+			// Stall until the test releases the handler.
+			<-release
+		},
+	}
+	d := newFakeDeck(t, s, WithRequestTimeout(50*time.Millisecond))
+
+	err := d.UpdateTitle(t.Context(), "title")
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+	if !strings.Contains(err.Error(), "update title timed out") {
+		t.Errorf("error should identify the timed out operation: %v", err)
+	}
+	close(release)
+
+	// Calls that finish in time are not affected.
+	if err := d.refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithRequestTimeoutInvalid(t *testing.T) {
+	d := &Deck{}
+	if err := WithRequestTimeout(0)(d); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestWithImageFetchTimeout(t *testing.T) {
+	png, err := os.ReadFile("testdata/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.png" {
+			// Stall until the test releases the handler.
+			<-release
+		}
+		_, _ = w.Write(png)
+	}))
+	t.Cleanup(images.Close)
+	t.Cleanup(func() { close(release) })
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	d := newFakeDeck(t, s, WithRequestTimeout(10*time.Second), WithImageFetchTimeout(50*time.Millisecond))
+
+	if _, err := d.fetchImage(images.URL+"/slow.png", false); err == nil {
+		t.Error("expected error for slow image but got none")
+	}
+	if _, err := d.fetchImage(images.URL+"/fast.png", false); err != nil {
+		t.Errorf("fast image should be fetched: %v", err)
+	}
+	// API calls are not affected by the image fetch timeout.
+	d.fresh = false
+	if err := d.refresh(t.Context()); err != nil {
+		t.Errorf("refresh should not time out: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package deck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/k1LoW/errors"
+	"google.golang.org/api/slides/v1"
+)
+
+const descriptionWatermark = "Watermark generated by deck"
+
+const (
+	defaultWatermarkOpacity  = 0.3
+	defaultWatermarkFontSize = 60.0
+	emuPerPoint              = 12700
+)
+
+// WatermarkOptions are the options of the watermark.
+type WatermarkOptions struct {
+	// X and Y are the position of the center of the watermark as a fraction of the page width and height.
+	// Zero means the center of the page.
+	X float64
+	Y float64
+	// Opacity is the opacity of the watermark from 0 to 1. Zero means 0.3.
+	// Because text in Google Slides cannot be transparent, the text color is blended with white.
+	Opacity float64
+	// Angle is the counterclockwise rotation of the watermark in degrees.
+	Angle float64
+	// FontSize is the font size of the watermark in points. Zero means 60.
+	FontSize float64
+}
+
+// ApplyWatermark adds a watermark text box to every slide.
+// Watermarks added previously are replaced.
+func (d *Deck) ApplyWatermark(ctx context.Context, text string, opts WatermarkOptions) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if text == "" {
+		return fmt.Errorf("watermark text is empty")
+	}
+	if opts.Opacity < 0 || opts.Opacity > 1 {
+		return fmt.Errorf("invalid watermark opacity: %v", opts.Opacity)
+	}
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+	reqs := d.removeWatermarkRequests()
+	for _, s := range d.presentation.Slides {
+		reqs = append(reqs, d.watermarkRequests(s.ObjectId, text, opts)...)
+	}
+	if len(reqs) == 0 {
+		return nil
+	}
+	if err := d.batchUpdate(ctx, reqs); err != nil {
+		return fmt.Errorf("failed to apply watermark: %w", err)
+	}
+	d.logger.Info("applied watermark", slog.Int("slides", len(d.presentation.Slides)))
+	return d.refresh(ctx)
+}
+
+// RemoveWatermark deletes the watermarks added by ApplyWatermark from every slide.
+func (d *Deck) RemoveWatermark(ctx context.Context) (err error) {
+	defer func() {
+		err = errors.WithStack(err)
+	}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.refresh(ctx); err != nil {
+		return err
+	}
+	reqs := d.removeWatermarkRequests()
+	if len(reqs) == 0 {
+		return nil
+	}
+	if err := d.batchUpdate(ctx, reqs); err != nil {
+		return fmt.Errorf("failed to remove watermark: %w", err)
+	}
+	d.logger.Info("removed watermark", slog.Int("count", len(reqs)))
+	return d.refresh(ctx)
+}
+
+func (d *Deck) removeWatermarkRequests() []*slides.Request {
+	var reqs []*slides.Request
+	for _, s := range d.presentation.Slides {
+		for _, element := range s.PageElements {
+			if element.Description != descriptionWatermark {
+				continue
+			}
+			reqs = append(reqs, &slides.Request{
+				DeleteObject: &slides.DeleteObjectRequest{
+					ObjectId: element.ObjectId,
+				},
+			})
+		}
+	}
+	return reqs
+}
+
+func (d *Deck) watermarkRequests(pageObjectID, text string, opts WatermarkOptions) []*slides.Request {
+	pageWidth, pageHeight := float64(defaultPageWidth), float64(defaultPageHeight)
+	if d.presentation.PageSize != nil && d.presentation.PageSize.Width != nil && d.presentation.PageSize.Height != nil {
+		pageWidth = d.presentation.PageSize.Width.Magnitude
+		pageHeight = d.presentation.PageSize.Height.Magnitude
+	}
+	x, y := opts.X, opts.Y
+	if x == 0 {
+		x = 0.5
+	}
+	if y == 0 {
+		y = 0.5
+	}
+	opacity := opts.Opacity
+	if opacity == 0 {
+		opacity = defaultWatermarkOpacity
+	}
+	fontSize := opts.FontSize
+	if fontSize == 0 {
+		fontSize = defaultWatermarkFontSize
+	}
+
+	width := pageWidth
+	height := fontSize * 2 * emuPerPoint
+	// Rotate around the center of the text box.
+	rad := opts.Angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	cx, cy := pageWidth*x, pageHeight*y
+	transform := &slides.AffineTransform{
+		ScaleX:     cos,
+		ShearX:     sin,
+		ShearY:     -sin,
+		ScaleY:     cos,
+		TranslateX: cx - (cos*width/2 + sin*height/2),
+		TranslateY: cy - (-sin*width/2 + cos*height/2),
+		Unit:       "EMU",
+	}
+	// Gray blended with the white background.
+	gray := 1 - opacity*0.5
+
+	objectID := fmt.Sprintf("watermark-%s", uuid.New().String())
+	return []*slides.Request{{
+		CreateShape: &slides.CreateShapeRequest{
+			ObjectId: objectID,
+			ElementProperties: &slides.PageElementProperties{
+				PageObjectId: pageObjectID,
+				Size: &slides.Size{
+					Width:  &slides.Dimension{Magnitude: width, Unit: "EMU"},
+					Height: &slides.Dimension{Magnitude: height, Unit: "EMU"},
+				},
+				Transform: transform,
+			},
+			ShapeType: "TEXT_BOX",
+		},
+	}, {
+		InsertText: &slides.InsertTextRequest{
+			ObjectId: objectID,
+			Text:     text,
+		},
+	}, {
+		UpdateTextStyle: &slides.UpdateTextStyleRequest{
+			ObjectId: objectID,
+			Style: &slides.TextStyle{
+				Bold:     true,
+				FontSize: &slides.Dimension{Magnitude: fontSize, Unit: "PT"},
+				ForegroundColor: &slides.OptionalColor{
+					OpaqueColor: &slides.OpaqueColor{
+						RgbColor: &slides.RgbColor{Red: gray, Green: gray, Blue: gray},
+					},
+				},
+			},
+			TextRange: &slides.Range{Type: "ALL"},
+			Fields:    "bold,fontSize,foregroundColor",
+		},
+	}, {
+		UpdateParagraphStyle: &slides.UpdateParagraphStyleRequest{
+			ObjectId:  objectID,
+			Style:     &slides.ParagraphStyle{Alignment: "CENTER"},
+			TextRange: &slides.Range{Type: "ALL"},
+			Fields:    "alignment",
+		},
+	}, {
+		UpdatePageElementAltText: &slides.UpdatePageElementAltTextRequest{
+			ObjectId:    objectID,
+			Description: descriptionWatermark,
+		},
+	}}
+}
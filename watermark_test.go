@@ -0,0 +1,73 @@
+package deck
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/api/slides/v1"
+)
+
+func TestApplyWatermark(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a", "b")}
+	d := newFakeDeck(t, s)
+
+	if err := d.ApplyWatermark(t.Context(), "DRAFT", WatermarkOptions{Angle: 30}); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.batchUpdates) != 1 {
+		t.Fatalf("got %d batch updates, want 1", len(s.batchUpdates))
+	}
+	pages := map[string]bool{}
+	created := map[string]bool{}
+	for _, r := range s.batchUpdates[0].Requests {
+		if r.CreateShape != nil {
+			pages[r.CreateShape.ElementProperties.PageObjectId] = true
+			tr := r.CreateShape.ElementProperties.Transform
+			if math.Abs(tr.ScaleX-math.Cos(math.Pi/6)) > 1e-9 {
+				t.Errorf("got scaleX %v, want rotation of 30 degrees", tr.ScaleX)
+			}
+		}
+		if r.UpdatePageElementAltText != nil && r.UpdatePageElementAltText.Description == descriptionWatermark {
+			created[r.UpdatePageElementAltText.ObjectId] = true
+		}
+	}
+	if !pages["slide-0"] || !pages["slide-1"] || len(created) != 2 {
+		t.Errorf("watermark should be added to each slide: pages %v, watermarks %v", pages, created)
+	}
+
+	// The fake server does not apply requests, so add the watermarks to the presentation by hand.
+	for i, sl := range s.presentation.Slides {
+		sl.PageElements = append(sl.PageElements, &slides.PageElement{
+			ObjectId:    "watermark-" + string(rune('0'+i)),
+			Description: descriptionWatermark,
+			Shape:       &slides.Shape{ShapeType: "TEXT_BOX"},
+		})
+	}
+	d.fresh = false
+	if err := d.RemoveWatermark(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.batchUpdates) != 2 {
+		t.Fatalf("got %d batch updates, want 2", len(s.batchUpdates))
+	}
+	var deleted []string
+	for _, r := range s.batchUpdates[1].Requests {
+		if r.DeleteObject != nil {
+			deleted = append(deleted, r.DeleteObject.ObjectId)
+		}
+	}
+	if len(deleted) != 2 || deleted[0] != "watermark-0" || deleted[1] != "watermark-1" {
+		t.Errorf("got deleted %v, want watermarks of both slides", deleted)
+	}
+}
+
+func TestApplyWatermarkInvalid(t *testing.T) {
+	s := &fakeServer{presentation: newFakePresentation("a")}
+	d := newFakeDeck(t, s)
+	if err := d.ApplyWatermark(t.Context(), "", WatermarkOptions{}); err == nil {
+		t.Error("expected error but got none")
+	}
+	if err := d.ApplyWatermark(t.Context(), "DRAFT", WatermarkOptions{Opacity: 2}); err == nil {
+		t.Error("expected error but got none")
+	}
+}